@@ -9,6 +9,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/route53 v1.29.4
 	github.com/aws/aws-sdk-go-v2/service/ssm v1.37.4
 	github.com/bwmarrin/discordgo v0.27.1
+	go.etcd.io/bbolt v1.3.8
 )
 
 require (
@@ -25,5 +26,5 @@ require (
 	github.com/gorilla/websocket v1.4.2 // indirect
 	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	golang.org/x/crypto v0.0.0-20220314234659-1baeb1ce4c0b // indirect
-	golang.org/x/sys v0.1.0 // indirect
+	golang.org/x/sys v0.4.0 // indirect
 )
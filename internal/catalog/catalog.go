@@ -0,0 +1,187 @@
+package catalog
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// DefaultLocale is used when a message is missing in the requested locale
+const DefaultLocale = "en-US"
+
+//go:embed locales/*.json
+var embedded embed.FS
+
+// Catalog holds the message templates of every locale, keyed by locale and then message key
+type Catalog struct {
+	templates map[string]map[string]*template.Template
+}
+
+// Load reads the embedded locales and then the locale files in dir, if set.
+// Files are named after the locale, e.g. "en-US.json", and map message keys to templates.
+// Messages in dir replace the embedded message with the same key.
+func Load(dir string) (*Catalog, error) {
+	c := &Catalog{templates: make(map[string]map[string]*template.Template)}
+
+	if err := c.loadFS(embedded, "locales"); err != nil {
+		return nil, err
+	}
+	if dir != "" {
+		if err := c.loadFS(os.DirFS(dir), "."); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// loadFS parses every locale file in the directory of fsys
+func (c *Catalog) loadFS(fsys fs.FS, dir string) error {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, p := range paths {
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("invalid locale file %v: %v", p, err)
+		}
+
+		locale := strings.TrimSuffix(path.Base(p), ".json")
+		if c.templates[locale] == nil {
+			c.templates[locale] = make(map[string]*template.Template)
+		}
+		for key, text := range messages {
+			tmpl, err := template.New(key).Option("missingkey=zero").Parse(text)
+			if err != nil {
+				return fmt.Errorf("invalid message %v in %v: %v", key, p, err)
+			}
+			c.templates[locale][key] = tmpl
+		}
+	}
+
+	return nil
+}
+
+// Locales returns every locale in the catalog
+func (c *Catalog) Locales() []string {
+	locales := make([]string, 0, len(c.templates))
+	for locale := range c.templates {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+
+	return locales
+}
+
+// Has checks if the locale, or its language without the region, is in the catalog
+func (c *Catalog) Has(locale string) bool {
+	if _, ok := c.templates[locale]; ok {
+		return true
+	}
+	language, _, _ := strings.Cut(locale, "-")
+	_, ok := c.templates[language]
+
+	return ok
+}
+
+// Get renders the message in the locale. It falls back to the locale's language, then the default locale and
+// then the key itself.
+func (c *Catalog) Get(locale string, key string, data any) string {
+	tmpl := c.lookup(locale, key)
+	if tmpl == nil {
+		log.Printf("Missing message %v", key)
+		return key
+	}
+
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		log.Printf("Error rendering message %v: %v", key, err)
+		return key
+	}
+
+	return b.String()
+}
+
+// Localizations renders the message in every locale other than the default that defines it
+func (c *Catalog) Localizations(key string, data any) map[string]string {
+	localizations := make(map[string]string)
+	for _, locale := range c.Locales() {
+		if locale == DefaultLocale {
+			continue
+		}
+
+		_, ok := c.templates[locale][key]
+		if ok {
+			localizations[locale] = c.Get(locale, key, data)
+		}
+	}
+
+	return localizations
+}
+
+// lookup finds the template for the key in the locale or one of its fallbacks
+func (c *Catalog) lookup(locale string, key string) *template.Template {
+	language, _, _ := strings.Cut(locale, "-")
+	for _, l := range []string{locale, language, DefaultLocale} {
+		if tmpl, ok := c.templates[l][key]; ok {
+			return tmpl
+		}
+	}
+
+	return nil
+}
+
+// Duration renders a duration in whole minutes or seconds, e.g. "5 minutes" or "10 seconds"
+func (c *Catalog) Duration(locale string, d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		if d == time.Minute {
+			return c.Get(locale, "time.minute", nil)
+		}
+		return c.Get(locale, "time.minutes", map[string]any{"Count": int(d / time.Minute)})
+	}
+
+	if d == time.Second {
+		return c.Get(locale, "time.second", nil)
+	}
+	return c.Get(locale, "time.seconds", map[string]any{"Count": int(d / time.Second)})
+}
+
+var (
+	defaultCatalog     *Catalog
+	defaultCatalogOnce sync.Once
+)
+
+// Default returns the catalog with the locales in the CATALOG_DIR environment variable, falling back to the embedded
+// locales if they can't be loaded
+func Default() *Catalog {
+	defaultCatalogOnce.Do(func() {
+		var err error
+		defaultCatalog, err = Load(os.Getenv("CATALOG_DIR"))
+		if err != nil {
+			log.Printf("Error loading CATALOG_DIR: %v", err)
+			defaultCatalog, err = Load("")
+			if err != nil {
+				log.Fatalf("Error loading embedded catalog: %v", err)
+			}
+		}
+	})
+
+	return defaultCatalog
+}
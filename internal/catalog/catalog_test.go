@@ -0,0 +1,170 @@
+package catalog
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEmbeddedLocales(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(c.Locales(), []string{"en-US", "ja"}) {
+		t.Errorf("Locales() = %v", c.Locales())
+	}
+
+	// Every translated message needs a default, except the descriptions of commands, which are defined with them
+	for locale, templates := range c.templates {
+		for key := range templates {
+			if strings.HasPrefix(key, "command.") {
+				continue
+			}
+			if _, ok := c.templates[DefaultLocale][key]; !ok {
+				t.Errorf("%v message %v has no %v message", locale, key, DefaultLocale)
+			}
+		}
+	}
+}
+
+func TestGet(t *testing.T) {
+	c := loadTestCatalog(t, map[string]string{
+		"en-US.json": `{"greeting": "Hello {{.Name}}", "only_default": "Default", "broken": "{{.Name.First}}"}`,
+		"ja.json":    `{"greeting": "こんにちは {{.Name}}"}`,
+		"pt.json":    `{"greeting": "Olá {{.Name}}"}`,
+	})
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		data   any
+		want   string
+	}{
+		{name: "default locale", locale: "en-US", key: "greeting", data: map[string]any{"Name": "Ash"}, want: "Hello Ash"},
+		{name: "locale", locale: "ja", key: "greeting", data: map[string]any{"Name": "Ash"}, want: "こんにちは Ash"},
+		{name: "language", locale: "pt-BR", key: "greeting", data: map[string]any{"Name": "Ash"}, want: "Olá Ash"},
+		{name: "missing in locale", locale: "ja", key: "only_default", want: "Default"},
+		{name: "unknown locale", locale: "fr", key: "greeting", data: map[string]any{"Name": "Ash"}, want: "Hello Ash"},
+		{name: "missing data", locale: "en-US", key: "greeting", data: map[string]string{}, want: "Hello "},
+		{name: "unknown key", locale: "en-US", key: "unknown", want: "unknown"},
+		{name: "render error", locale: "en-US", key: "broken", data: map[string]any{"Name": "Ash"}, want: "broken"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.Get(tt.locale, tt.key, tt.data); got != tt.want {
+				t.Errorf("Get(%q, %q) = %q, want %q", tt.locale, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHas(t *testing.T) {
+	c := loadTestCatalog(t, map[string]string{"pt.json": `{}`})
+
+	tests := []struct {
+		locale string
+		want   bool
+	}{
+		{locale: "en-US", want: true},
+		{locale: "ja", want: true},
+		{locale: "pt-BR", want: true},
+		{locale: "en-GB", want: false},
+		{locale: "fr", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := c.Has(tt.locale); got != tt.want {
+			t.Errorf("Has(%q) = %v, want %v", tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestLocalizations(t *testing.T) {
+	c := loadTestCatalog(t, map[string]string{
+		"en-US.json": `{"greeting": "Hello", "only_default": "Default"}`,
+		"ja.json":    `{"greeting": "こんにちは"}`,
+	})
+
+	if got, want := c.Localizations("greeting", nil), map[string]string{"ja": "こんにちは"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Localizations(greeting) = %v, want %v", got, want)
+	}
+	if got := c.Localizations("only_default", nil); len(got) != 0 {
+		t.Errorf("Localizations(only_default) = %v, want none", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	c, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		locale string
+		d      time.Duration
+		want   string
+	}{
+		{locale: "en-US", d: 5 * time.Minute, want: "5 minutes"},
+		{locale: "en-US", d: time.Minute, want: "1 minute"},
+		{locale: "en-US", d: 90 * time.Second, want: "90 seconds"},
+		{locale: "en-US", d: time.Second, want: "1 second"},
+		{locale: "en-US", d: 10 * time.Second, want: "10 seconds"},
+	}
+
+	for _, tt := range tests {
+		if got := c.Duration(tt.locale, tt.d); got != tt.want {
+			t.Errorf("Duration(%q, %v) = %q, want %q", tt.locale, tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{name: "invalid json", files: map[string]string{"en-US.json": `{`}},
+		{name: "invalid template", files: map[string]string{"en-US.json": `{"greeting": "{{.Name"}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Load(writeLocales(t, tt.files)); err == nil {
+				t.Error("Load() error = nil, want an error")
+			}
+		})
+	}
+}
+
+// loadTestCatalog loads the embedded locales with the locale files replacing their messages
+func loadTestCatalog(t *testing.T, files map[string]string) *Catalog {
+	t.Helper()
+
+	c, err := Load(writeLocales(t, files))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	return c
+}
+
+// writeLocales writes the locale files to a temporary directory
+func writeLocales(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
@@ -0,0 +1,146 @@
+package cooldown
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rule allows Limit uses within Window. A Limit of 1 is a plain cooldown.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Limiter keeps track of command usage per user, per subcommand and per server
+type Limiter struct {
+	mu sync.Mutex
+
+	// User limits how many commands a single user can run
+	User Rule
+
+	// Commands limits how often a single user can run each subcommand
+	Commands map[string]Rule
+
+	// Global limits how often each subcommand can be run on a server by anyone
+	Global map[string]Rule
+
+	uses map[string][]time.Time
+}
+
+// New returns a Limiter using the given rules
+func New(user Rule, commands map[string]Rule, global map[string]Rule) *Limiter {
+	return &Limiter{
+		User:     user,
+		Commands: commands,
+		Global:   global,
+		uses:     make(map[string][]time.Time),
+	}
+}
+
+// Allow checks whether the user can run the subcommand on the server right now. If not, it returns the time the user
+// can retry. Uses are only recorded when the command is allowed.
+func (l *Limiter) Allow(userID string, server string, command string, now time.Time) (bool, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	checks := map[string]Rule{
+		"user:" + userID:                    l.User,
+		"command:" + command + ":" + userID: l.Commands[command],
+		"global:" + server + ":" + command:  l.Global[command],
+	}
+
+	var retryAt time.Time
+	for key, rule := range checks {
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			continue
+		}
+
+		uses := l.prune(key, rule, now)
+		if len(uses) >= rule.Limit {
+			if next := uses[len(uses)-rule.Limit].Add(rule.Window); next.After(retryAt) {
+				retryAt = next
+			}
+		}
+	}
+	if !retryAt.IsZero() {
+		return false, retryAt
+	}
+
+	for key, rule := range checks {
+		if rule.Limit <= 0 || rule.Window <= 0 {
+			continue
+		}
+		l.uses[key] = append(l.uses[key], now)
+	}
+
+	return true, time.Time{}
+}
+
+// prune drops uses that are outside of the rule's window
+func (l *Limiter) prune(key string, rule Rule, now time.Time) []time.Time {
+	uses := l.uses[key]
+	i := 0
+	for i < len(uses) && !uses[i].Add(rule.Window).After(now) {
+		i++
+	}
+	uses = uses[i:]
+
+	if len(uses) == 0 {
+		delete(l.uses, key)
+	} else {
+		l.uses[key] = uses
+	}
+
+	return uses
+}
+
+// ParseRule parses a rule in the form of "<limit>/<window>", e.g. "3/1m".
+// A bare duration such as "5m" is a cooldown of one use per window.
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Rule{}, nil
+	}
+
+	limit, window, found := strings.Cut(s, "/")
+	if !found {
+		limit, window = "1", s
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n < 0 {
+		return Rule{}, fmt.Errorf("invalid limit in rule %q", s)
+	}
+	d, err := time.ParseDuration(window)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid window in rule %q: %v", s, err)
+	}
+
+	return Rule{Limit: n, Window: d}, nil
+}
+
+// ParseRules parses a comma separated list of "<subcommand>=<rule>" pairs, e.g. "start=1/5m,stop=5m"
+func ParseRules(s string) (map[string]Rule, error) {
+	rules := make(map[string]Rule)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rule %q", pair)
+		}
+
+		rule, err := ParseRule(value)
+		if err != nil {
+			return nil, err
+		}
+		rules[strings.TrimSpace(name)] = rule
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,171 @@
+package cooldown
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Rule
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: Rule{}},
+		{name: "limit and window", input: "3/1m", want: Rule{Limit: 3, Window: time.Minute}},
+		{name: "bare duration", input: "5m", want: Rule{Limit: 1, Window: 5 * time.Minute}},
+		{name: "spaces", input: " 2/30s ", want: Rule{Limit: 2, Window: 30 * time.Second}},
+		{name: "zero limit", input: "0/1m", want: Rule{Limit: 0, Window: time.Minute}},
+		{name: "negative limit", input: "-1/1m", wantErr: true},
+		{name: "invalid limit", input: "x/1m", wantErr: true},
+		{name: "invalid window", input: "1/soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRule(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRule(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseRule(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]Rule
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: map[string]Rule{}},
+		{
+			name:  "several",
+			input: "start=1/5m, stop=2m,",
+			want:  map[string]Rule{"start": {Limit: 1, Window: 5 * time.Minute}, "stop": {Limit: 1, Window: 2 * time.Minute}},
+		},
+		{name: "missing rule", input: "start", wantErr: true},
+		{name: "invalid rule", input: "start=x/1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRules(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRules(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRules(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterAllow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type call struct {
+		user      string
+		server    string
+		command   string
+		after     time.Duration
+		allowed   bool
+		retryWait time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		user     Rule
+		commands map[string]Rule
+		global   map[string]Rule
+		calls    []call
+	}{
+		{
+			name: "no rules",
+			calls: []call{
+				{user: "a", command: "start", allowed: true},
+				{user: "a", command: "start", allowed: true},
+			},
+		},
+		{
+			name: "user limit",
+			user: Rule{Limit: 2, Window: time.Minute},
+			calls: []call{
+				{user: "a", command: "status", allowed: true},
+				{user: "a", command: "start", after: 10 * time.Second, allowed: true},
+				{user: "a", command: "stop", after: 20 * time.Second, retryWait: time.Minute},
+				{user: "b", command: "stop", after: 20 * time.Second, allowed: true},
+				{user: "a", command: "stop", after: time.Minute, allowed: true},
+			},
+		},
+		{
+			name:     "command cooldown",
+			commands: map[string]Rule{"start": {Limit: 1, Window: 5 * time.Minute}},
+			calls: []call{
+				{user: "a", command: "start", allowed: true},
+				{user: "a", command: "status", after: time.Minute, allowed: true},
+				{user: "a", command: "start", after: time.Minute, retryWait: 5 * time.Minute},
+				{user: "b", command: "start", after: time.Minute, allowed: true},
+				{user: "a", command: "start", after: 5 * time.Minute, allowed: true},
+			},
+		},
+		{
+			name:   "global cooldown",
+			global: map[string]Rule{"start": {Limit: 1, Window: 2 * time.Minute}},
+			calls: []call{
+				{user: "a", command: "start", allowed: true},
+				{user: "b", command: "start", after: time.Minute, retryWait: 2 * time.Minute},
+				{user: "b", command: "stop", after: time.Minute, allowed: true},
+				{user: "b", command: "start", after: 2 * time.Minute, allowed: true},
+			},
+		},
+		{
+			name:   "global cooldown per server",
+			global: map[string]Rule{"start": {Limit: 1, Window: 2 * time.Minute}},
+			calls: []call{
+				{user: "a", server: "kanto", command: "start", allowed: true},
+				{user: "b", server: "johto", command: "start", after: time.Minute, allowed: true},
+				{user: "b", server: "kanto", command: "start", after: time.Minute, retryWait: 2 * time.Minute},
+			},
+		},
+		{
+			name:     "denied calls aren't recorded",
+			commands: map[string]Rule{"start": {Limit: 1, Window: time.Minute}},
+			global:   map[string]Rule{"start": {Limit: 1, Window: 2 * time.Minute}},
+			calls: []call{
+				{user: "a", command: "start", allowed: true},
+				{user: "b", command: "start", after: 30 * time.Second, retryWait: 2 * time.Minute},
+				{user: "b", command: "start", after: 2 * time.Minute, allowed: true},
+			},
+		},
+		{
+			name:     "latest retry wins",
+			user:     Rule{Limit: 1, Window: time.Minute},
+			commands: map[string]Rule{"start": {Limit: 1, Window: 5 * time.Minute}},
+			calls: []call{
+				{user: "a", command: "start", allowed: true},
+				{user: "a", command: "start", after: 30 * time.Second, retryWait: 5 * time.Minute},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.user, tt.commands, tt.global)
+			for n, c := range tt.calls {
+				allowed, retryAt := l.Allow(c.user, c.server, c.command, start.Add(c.after))
+				if allowed != c.allowed {
+					t.Fatalf("call %d: Allow(%q, %q) = %v, want %v", n, c.user, c.command, allowed, c.allowed)
+				}
+				if !allowed && !retryAt.Equal(start.Add(c.retryWait)) {
+					t.Errorf("call %d: retry at %v, want %v", n, retryAt.Sub(start), c.retryWait)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package discord
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/mcstatus"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	// suggestionTTL is how long fetched names are suggested before they are fetched again
+	suggestionTTL = 30 * time.Second
+
+	// suggestionWait is how long to wait for names that aren't cached, since Discord only waits 3 seconds for
+	// suggestions
+	suggestionWait = 2 * time.Second
+
+	// maxSuggestions is the most choices Discord accepts
+	maxSuggestions = 25
+)
+
+// cachedNames are names fetched from a server
+type cachedNames struct {
+	names   []string
+	expires time.Time
+
+	// fetching is closed when the fetch in progress is done
+	fetching chan struct{}
+}
+
+var (
+	// suggestionCache is keyed by the kind of names and the server
+	suggestionCache   = make(map[string]*cachedNames)
+	suggestionCacheMu sync.Mutex
+)
+
+// AutocompleteHandlers are keyed by command name
+var AutocompleteHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+	"pixelmon": func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		focused := focusedOption(i)
+		if focused == nil {
+			return
+		}
+
+		var names []string
+		switch focused.Name {
+		case "server":
+			for _, srv := range getGuildServers(i.GuildID) {
+				names = append(names, srv.Name)
+			}
+		case "username":
+			srv, ok := getServer(i)
+			if !ok {
+				break
+			}
+
+			// The user's own accounts are the most likely to be whitelisted again
+			linked, _, err := accounts.Get(getUserID(i))
+			if err != nil {
+				log.Printf("Error: %v", err)
+			}
+			names = append(names, linked...)
+
+			// Fetch both at once to answer before Discord stops waiting
+			var online, whitelisted []string
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				// Not retried, since the suggestions are only useful while Discord waits for them
+				online = getCachedNames("online:"+srv.Key(), func(ctx context.Context) ([]string, error) {
+					return mcstatus.GetPlayerNames(ctx, srv.Address())
+				})
+			}()
+			go func() {
+				defer wg.Done()
+				whitelisted = getCachedNames("whitelist:"+srv.Key(), func(ctx context.Context) ([]string, error) {
+					return pixelmon.GetWhitelist(ctx, srv)
+				})
+			}()
+			wg.Wait()
+
+			names = append(names, online...)
+			names = append(names, whitelisted...)
+		case "key":
+			names = pixelmon.PropertyKeys()
+		case "rule":
+			names = pixelmon.GameRuleNames()
+		}
+
+		respondSuggestions(s, i, names, focused.StringValue())
+	},
+	"seigetsu": func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		focused := focusedOption(i)
+		if focused == nil {
+			return
+		}
+
+		var names []string
+		switch focused.Name {
+		case "name":
+			// Only the link subcommand completes names, with the servers guilds can link
+			for _, srv := range pixelmon.Servers() {
+				names = append(names, srv.Name)
+			}
+		}
+
+		respondSuggestions(s, i, names, focused.StringValue())
+	},
+}
+
+// focusedOption gets the option of the interaction's subcommand the user is typing, or nil if there is none
+func focusedOption(i *discordgo.InteractionCreate) *discordgo.ApplicationCommandInteractionDataOption {
+	for _, option := range subcommandOptions(i) {
+		if option.Focused {
+			return option
+		}
+	}
+
+	return nil
+}
+
+// respondSuggestions suggests the names that match what the user typed
+func respondSuggestions(s *discordgo.Session, i *discordgo.InteractionCreate, names []string, typed string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+		Data: &discordgo.InteractionResponseData{
+			Choices: suggestionChoices(names, typed),
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
+
+// getCachedNames returns the cached names or fetches them if they expired. If fetching takes longer than
+// suggestionWait, the expired names are returned and the fetch keeps going in the background for the next time.
+// Failed fetches keep the expired names until they expire again, so an offline server isn't asked on every keystroke.
+func getCachedNames(key string, fetch func(ctx context.Context) ([]string, error)) []string {
+	suggestionCacheMu.Lock()
+	cached, ok := suggestionCache[key]
+	if !ok {
+		cached = &cachedNames{}
+		suggestionCache[key] = cached
+	}
+	if time.Now().Before(cached.expires) {
+		names := cached.names
+		suggestionCacheMu.Unlock()
+		return names
+	}
+
+	if cached.fetching == nil {
+		done := make(chan struct{})
+		cached.fetching = done
+
+		go func() {
+			ctx, cancel := requestContext()
+			defer cancel()
+
+			names, err := fetch(ctx)
+			if err != nil {
+				log.Printf("Error fetching %v: %v", key, err)
+			}
+
+			suggestionCacheMu.Lock()
+			if err == nil {
+				cached.names = names
+			}
+			cached.expires = time.Now().Add(suggestionTTL)
+			cached.fetching = nil
+			suggestionCacheMu.Unlock()
+
+			close(done)
+		}()
+	}
+	fetching := cached.fetching
+	suggestionCacheMu.Unlock()
+
+	select {
+	case <-fetching:
+	case <-time.After(suggestionWait):
+	}
+
+	suggestionCacheMu.Lock()
+	defer suggestionCacheMu.Unlock()
+
+	return cached.names
+}
+
+// forgetCachedNames makes the names be fetched again the next time they are suggested
+func forgetCachedNames(key string) {
+	suggestionCacheMu.Lock()
+	defer suggestionCacheMu.Unlock()
+
+	if cached, ok := suggestionCache[key]; ok {
+		cached.expires = time.Time{}
+	}
+}
+
+// suggestionChoices returns the names that contain the typed value, without duplicates, in order
+func suggestionChoices(names []string, typed string) []*discordgo.ApplicationCommandOptionChoice {
+	typed = strings.ToLower(strings.TrimSpace(typed))
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, maxSuggestions)
+	seen := make(map[string]bool)
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if name == "" || seen[lower] || !strings.Contains(lower, typed) {
+			continue
+		}
+		seen[lower] = true
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+		if len(choices) == maxSuggestions {
+			break
+		}
+	}
+
+	return choices
+}
+
+// linkAccount remembers that the user whitelisted the Minecraft username
+func linkAccount(userID string, username string) {
+	err := accounts.Update(userID, func(names *[]string) error {
+		for _, name := range *names {
+			if strings.EqualFold(name, username) {
+				return nil
+			}
+		}
+		*names = append(*names, username)
+
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error linking account: %v", err)
+	}
+}
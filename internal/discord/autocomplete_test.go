@@ -0,0 +1,84 @@
+package discord
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestLinkAutocomplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "servers.json")
+	if err := os.WriteFile(path, []byte(`[{"name": "Kanto", "instance_id": "i-1"}, {"name": "Johto", "instance_id": "i-2"}]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PIXELMON_SERVERS", path)
+
+	fake := &fakeTransport{}
+	s := newTestSession(t, fake)
+
+	// Autocomplete is dispatched by the name of the top-level command
+	i := newTestCommand("link", &discordgo.ApplicationCommandInteractionDataOption{
+		Name: "name", Type: discordgo.ApplicationCommandOptionString, Value: "to", Focused: true,
+	})
+	i.Type = discordgo.InteractionApplicationCommandAutocomplete
+	data := i.Data.(discordgo.ApplicationCommandInteractionData)
+	data.Name = "seigetsu"
+	data.Options = []*discordgo.ApplicationCommandInteractionDataOption{{Name: "config", Type: discordgo.ApplicationCommandOptionSubCommandGroup, Options: data.Options}}
+	i.Data = data
+
+	handler, ok := AutocompleteHandlers[data.Name]
+	if !ok {
+		t.Fatalf("no autocomplete handler for /%v", data.Name)
+	}
+	handler(s, i)
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("sent %v requests, want the suggestions", len(fake.requests))
+	}
+	response := fake.requests[0].Body
+	choices := response["data"].(map[string]any)["choices"]
+	want := []any{
+		map[string]any{"name": "Kanto", "value": "Kanto"},
+		map[string]any{"name": "Johto", "value": "Johto"},
+	}
+	if response["type"] != float64(discordgo.InteractionApplicationCommandAutocompleteResult) || !reflect.DeepEqual(choices, want) {
+		t.Errorf("responded with %+v, want choices %+v", response, want)
+	}
+}
+
+func TestSuggestionChoices(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		typed string
+		want  []string
+	}{
+		{name: "everything", names: []string{"Ash", "Misty"}, typed: "", want: []string{"Ash", "Misty"}},
+		{name: "contains", names: []string{"Ash", "Misty", "Brock"}, typed: " S ", want: []string{"Ash", "Misty"}},
+		{name: "duplicates", names: []string{"Ash", "ash", "", "Misty"}, typed: "", want: []string{"Ash", "Misty"}},
+		{name: "none", names: []string{"Ash"}, typed: "gary", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, choice := range suggestionChoices(tt.names, tt.typed) {
+				got = append(got, choice.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggestionChoices(%q) = %v, want %v", tt.typed, got, tt.want)
+			}
+		})
+	}
+
+	names := make([]string, 30)
+	for n := range names {
+		names[n] = string(rune('a'+n%26)) + string(rune('a'+n/26))
+	}
+	if got := suggestionChoices(names, ""); len(got) != maxSuggestions {
+		t.Errorf("suggestionChoices() returned %v choices, want %v", len(got), maxSuggestions)
+	}
+}
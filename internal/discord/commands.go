@@ -2,13 +2,14 @@ package discord
 
 import (
 	"log"
-	"strconv"
 
 	"github.com/bwmarrin/discordgo"
-	"github.com/kn-lim/seigetsu-bot/internal/mcstatus"
 	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
 )
 
+// adminPermission hides commands from members without the Administrator permission by default
+var adminPermission int64 = discordgo.PermissionAdministrator
+
 var (
 	Commands = []*discordgo.ApplicationCommand{
 		{
@@ -19,34 +20,273 @@ var (
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "status",
 					Description: "Get the status of the Pixelmon server",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "start",
 					Description: "Starts the Pixelmon server",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "stop",
 					Description: "Stops the Pixelmon server",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
-					Name:        "whitelist",
-					Description: "Adds a user to the whitelist of the Pixelmon server",
+					Name:        "restart",
+					Description: "Restarts the Pixelmon service without stopping the server",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "dashboard",
+					Description: "Posts a live-updating status dashboard in this channel (admin only)",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "resize",
+					Description: "Changes the EC2 instance type of the stopped Pixelmon server (admin only)",
 					Options: []*discordgo.ApplicationCommandOption{
 						{
 							Type:        discordgo.ApplicationCommandOptionString,
-							Name:        "username",
-							Description: "Minecraft username to whitelist",
+							Name:        "type",
+							Description: "Instance type to change to",
 							Required:    true,
+							Choices:     instanceTypeChoices(),
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionBoolean,
+							Name:        "revert",
+							Description: "Revert to the default instance type at the next stop",
+						},
+						serverOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "properties",
+					Description: "Views and changes the server.properties of the Pixelmon server (admin only)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "get",
+							Description: "Shows the value of a server property",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:         discordgo.ApplicationCommandOptionString,
+									Name:         "key",
+									Description:  "Server property to show",
+									Required:     true,
+									Autocomplete: true,
+								},
+								serverOption(),
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "set",
+							Description: "Changes the value of a server property",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:         discordgo.ApplicationCommandOptionString,
+									Name:         "key",
+									Description:  "Server property to change",
+									Required:     true,
+									Autocomplete: true,
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "value",
+									Description: "Value to change it to",
+									Required:    true,
+								},
+								serverOption(),
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "gamerule",
+					Description: "Shows or changes a game rule of the running Pixelmon server (admin only)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "rule",
+							Description:  "Game rule to show or change",
+							Required:     true,
+							Autocomplete: true,
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionString,
+							Name:        "value",
+							Description: "Value to change it to. Leave empty to show the current value",
+						},
+						serverOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "mods",
+					Description: "Lists the mods of the Pixelmon server and what players need to join it",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "permissions",
+					Description: "Views and changes who can use each subcommand (admin only)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "view",
+							Description: "Shows who can use each subcommand",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "subcommand",
+									Description: "Subcommand to show",
+									Choices:     subcommandChoices(),
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "allow",
+							Description: "Allows a role, user or permission to use a subcommand",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "subcommand",
+									Description: "Subcommand to change",
+									Required:    true,
+									Choices:     subcommandChoices(),
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Role to allow",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionUser,
+									Name:        "user",
+									Description: "User to allow",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "permission",
+									Description: "Discord permission to allow",
+									Choices:     permissionChoices(),
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "deny",
+							Description: "Denies a role or user from using a subcommand",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "subcommand",
+									Description: "Subcommand to change",
+									Required:    true,
+									Choices:     subcommandChoices(),
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Role to deny",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionUser,
+									Name:        "user",
+									Description: "User to deny",
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove",
+							Description: "Removes a role, user or permission from a subcommand's rule",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "subcommand",
+									Description: "Subcommand to change",
+									Required:    true,
+									Choices:     subcommandChoices(),
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Role to remove",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionUser,
+									Name:        "user",
+									Description: "User to remove",
+								},
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "permission",
+									Description: "Discord permission to remove",
+									Choices:     permissionChoices(),
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "reset",
+							Description: "Resets a subcommand's rule to the default",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "subcommand",
+									Description: "Subcommand to reset",
+									Required:    true,
+									Choices:     subcommandChoices(),
+								},
+							},
+						},
+					},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommand,
+					Name:        "whitelist",
+					Description: "Adds a user to the whitelist of the Pixelmon server",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:         discordgo.ApplicationCommandOptionString,
+							Name:         "username",
+							Description:  "Minecraft username to whitelist",
+							Required:     true,
+							Autocomplete: true,
 						},
+						serverOption(),
 					},
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
 					Name:        "online",
 					Description: "List number of online players on the Pixelmon server",
+					Options: []*discordgo.ApplicationCommandOption{
+						serverOption(),
+					},
 				},
 				{
 					Type:        discordgo.ApplicationCommandOptionSubCommand,
@@ -59,6 +299,106 @@ var (
 							Description: "Message to send to the Pixelmon server",
 							Required:    true,
 						},
+						serverOption(),
+					},
+				},
+			},
+		},
+		{
+			Name:                     "seigetsu",
+			Description:              "Seigetsu command",
+			DefaultMemberPermissions: &adminPermission,
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+					Name:        "config",
+					Description: "Views and changes the configuration of this server (admin only)",
+					Options: []*discordgo.ApplicationCommandOption{
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "view",
+							Description: "Shows the configuration",
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "link",
+							Description: "Links one of the bot's Pixelmon servers",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:         discordgo.ApplicationCommandOptionString,
+									Name:         "name",
+									Description:  "Name of the server",
+									Required:     true,
+									MaxLength:    32,
+									Autocomplete: true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "unlink",
+							Description: "Unlinks a Pixelmon server",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "name",
+									Description: "Name of the server",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "add-role",
+							Description: "Adds a role to the roles that subcommands require by default",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Role to add",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "remove-role",
+							Description: "Removes a role from the roles that subcommands require by default",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionRole,
+									Name:        "role",
+									Description: "Role to remove",
+									Required:    true,
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "channel",
+							Description: "Sets the channel where the bot announces when a server goes online or offline",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:         discordgo.ApplicationCommandOptionChannel,
+									Name:         "channel",
+									Description:  "Channel to announce in. Leave empty to stop announcing",
+									ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+								},
+							},
+						},
+						{
+							Type:        discordgo.ApplicationCommandOptionSubCommand,
+							Name:        "language",
+							Description: "Sets the language of the bot's messages",
+							Options: []*discordgo.ApplicationCommandOption{
+								{
+									Type:        discordgo.ApplicationCommandOptionString,
+									Name:        "language",
+									Description: "Language to use. Leave empty to use the server's locale",
+									Choices:     languageChoices(),
+								},
+							},
+						},
 					},
 				},
 			},
@@ -67,89 +407,64 @@ var (
 
 	CommandHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
 		"pixelmon": func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-			requiredRoles, err := getRequiredRoleIDs(s, i)
-			if err != nil {
-				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Content: ":red_circle:   Error! Something went wrong with getting the required role IDs!",
-					},
-				})
-				if err != nil {
-					log.Printf("Error: %v", err)
-				}
+			subcommand := i.ApplicationCommandData().Options[0].Name
 
+			// Check if user is allowed to use the subcommand
+			if !checkPermission(s, i, subcommand) {
 				return
 			}
 
-			switch i.ApplicationCommandData().Options[0].Name {
+			// Check if the guild has the chosen server
+			srv, ok := checkServer(s, i)
+			if !ok {
+				return
+			}
+
+			// Check if user is on cooldown, which only counts calls that are allowed
+			if !checkCooldown(s, i, srv, subcommand) {
+				return
+			}
+
+			ctx, cancel := requestContext()
+			defer cancel()
+
+			switch subcommand {
 			case "status":
 				// log.Println("/pixelmon status")
 
-				// Get status
-				msg, err := pixelmon.GetStatus()
-				if err != nil {
-					msg = err.Error()
-				}
-
-				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Content: msg,
-					},
-				})
-				if err != nil {
-					log.Printf("Error: %v", err)
-				}
+				respondStatus(s, i, srv, false)
 			case "start":
 				// log.Println("/pixelmon start")
 
-				// Check if user has the required role to use command
-				if !checkForMinecraftersRole(requiredRoles, s, i) {
-					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData{
-							Content: "You don't have the required role to use this command!",
-							Flags:   64,
-						},
-					})
-					if err != nil {
-						log.Printf("Error: %v", err)
-					}
+				handleStart(s, i, srv)
+			case "stop":
+				// log.Println("/pixelmon stop")
 
-					return
-				}
+				handleStop(s, i, srv)
+			case "restart":
+				// log.Println("/pixelmon restart")
 
-				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				content := getMessage(i, "restart.restarting", nil)
+				err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: pixelmon.Message[pixelmon.Starting],
+						Content: content,
 					},
 				})
 				if err != nil {
 					log.Printf("Error: %v", err)
 				}
 
-				// Start Pixelmon EC2 Instance
-				if err := pixelmon.Start(); err != nil {
-					log.Printf("Error: %v", err)
-
-					_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_Start],
-					})
-					if err != nil {
-						log.Fatalf("Error sending follow-up message: %v", err)
-					}
+				// Restart Pixelmon service
+				ctx, done := startOperation()
+				defer done()
+				ctx = withProgress(ctx, getLocale(i), content, editResponse(s, i))
 
-					return
-				}
-
-				// Start Pixelmon service
-				if err := pixelmon.StartPixelmon(); err != nil {
+				if err := pixelmon.RestartPixelmon(ctx, srv); err != nil {
 					log.Printf("Error: %v", err)
 
 					_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_Start],
+						Content: failureMessage(i, "restart.error", err),
 					})
 					if err != nil {
 						log.Fatalf("Error sending follow-up message: %v", err)
@@ -158,103 +473,48 @@ var (
 					return
 				}
 
-				_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-					Content: pixelmon.Message[pixelmon.Online],
+				_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+					Content: getMessage(i, "status.online", nil),
 				})
 				if err != nil {
 					log.Fatalf("Error sending follow-up message: %v", err)
 				}
-			case "stop":
-				// log.Println("/pixelmon stop")
-
-				// Check if user has the required role to use command
-				if !checkForMinecraftersRole(requiredRoles, s, i) {
-					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData{
-							Content: "You don't have the required role to use this command!",
-							Flags:   64,
-						},
-					})
-					if err != nil {
-						log.Printf("Error: %v", err)
-					}
-
-					return
-				}
+			case "dashboard":
+				// log.Println("/pixelmon dashboard")
 
-				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-					Type: discordgo.InteractionResponseChannelMessageWithSource,
-					Data: &discordgo.InteractionResponseData{
-						Content: pixelmon.Message[pixelmon.Stopping],
-					},
-				})
-				if err != nil {
-					log.Printf("Error: %v", err)
-				}
+				handleDashboard(s, i, srv)
+			case "resize":
+				// log.Println("/pixelmon resize")
 
-				// Stop Pixelmon service
-				if err := pixelmon.StopPixelmon(); err != nil {
-					log.Printf("Error: %v", err)
+				handleResize(s, i, srv)
+			case "properties":
+				// log.Println("/pixelmon properties")
 
-					_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_Stop],
-					})
-					if err != nil {
-						log.Fatalf("Error sending follow-up message: %v", err)
-					}
+				handleProperties(s, i, srv)
+			case "gamerule":
+				// log.Println("/pixelmon gamerule")
 
-					return
-				}
+				handleGameRule(s, i, srv)
+			case "mods":
+				// log.Println("/pixelmon mods")
 
-				// Stop Pixelmon EC2 Instance
-				if err := pixelmon.Stop(); err != nil {
-					log.Printf("Error: %v", err)
+				handleMods(s, i, srv)
+			case "permissions":
+				// log.Println("/pixelmon permissions")
 
-					_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_Stop],
-					})
-					if err != nil {
-						log.Fatalf("Error sending follow-up message: %v", err)
-					}
-
-					return
-				}
-
-				_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-					Content: pixelmon.Message[pixelmon.Offline],
-				})
-				if err != nil {
-					log.Fatalf("Error sending follow-up message: %v", err)
-				}
+				handlePermissions(s, i)
 			case "whitelist":
 				// log.Println("/pixelmon whitelist")
 
-				// Check if user has the required role to use command
-				if !checkForMinecraftersRole(requiredRoles, s, i) {
-					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-						Type: discordgo.InteractionResponseChannelMessageWithSource,
-						Data: &discordgo.InteractionResponseData{
-							Content: "You don't have the required role to use this command!",
-							Flags:   64,
-						},
-					})
-					if err != nil {
-						log.Printf("Error: %v", err)
-					}
-
-					return
-				}
-
 				// Check if server is online
-				isOnline, _, err := mcstatus.GetMCStatus()
+				status, err := pixelmon.GetServiceStatus(ctx, srv)
 				if err != nil {
 					log.Printf("Error: %v", err)
 
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Err_Status],
+							Content: getMessage(i, "status.error", nil),
 							Flags:   64,
 						},
 					})
@@ -264,11 +524,11 @@ var (
 
 					return
 				}
-				if !isOnline {
+				if !status.Online {
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Offline],
+							Content: getMessage(i, "status.offline", nil),
 							Flags:   64,
 						},
 					})
@@ -300,7 +560,7 @@ var (
 				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: pixelmon.Message[pixelmon.Whitelist] + "`" + username + "`",
+						Content: getMessage(i, "whitelist.sending", map[string]any{"Username": username}),
 					},
 				})
 				if err != nil {
@@ -308,11 +568,11 @@ var (
 				}
 
 				// Add name to whitelist
-				if err := pixelmon.AddToWhitelist(username); err != nil {
+				if err := pixelmon.AddToWhitelist(ctx, srv, username); err != nil {
 					log.Printf("Error: %v", err)
 
 					_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_Whitelist],
+						Content: getMessage(i, "whitelist.error", map[string]any{"Username": username}),
 					})
 					if err != nil {
 						log.Fatalf("Error sending follow-up message: %v", err)
@@ -321,8 +581,11 @@ var (
 					return
 				}
 
+				linkAccount(getUserID(i), username)
+				forgetCachedNames("whitelist:" + srv.Key())
+
 				_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-					Content: pixelmon.Message[pixelmon.Success_Whitelist] + "`" + username + "`",
+					Content: getMessage(i, "whitelist.success", map[string]any{"Username": username}),
 				})
 				if err != nil {
 					log.Fatalf("Error sending follow-up message: %v", err)
@@ -331,14 +594,14 @@ var (
 				// log.Println("/pixelmon online")
 
 				// Check if server is online
-				isOnline, _, err := mcstatus.GetMCStatus()
+				status, err := pixelmon.GetServiceStatus(ctx, srv)
 				if err != nil {
 					log.Printf("Error: %v", err)
 
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Err_Status],
+							Content: getMessage(i, "status.error", nil),
 							Flags:   64,
 						},
 					})
@@ -348,11 +611,11 @@ var (
 
 					return
 				}
-				if !isOnline {
+				if !status.Online {
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Offline],
+							Content: getMessage(i, "status.offline", nil),
 							Flags:   64,
 						},
 					})
@@ -363,14 +626,14 @@ var (
 					return
 				}
 
-				num, err := pixelmon.GetNumberOfPlayers()
+				num, err := pixelmon.GetNumberOfPlayers(ctx, srv)
 				if err != nil {
 					log.Printf("Error: %v", err)
 
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Err_NumPlayers],
+							Content: getMessage(i, "online.error", nil),
 						},
 					})
 					if err != nil {
@@ -383,7 +646,7 @@ var (
 				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: pixelmon.Message[pixelmon.NumPlayers] + strconv.Itoa(num),
+						Content: getMessage(i, "online.players", map[string]any{"Count": num}),
 					},
 				})
 				if err != nil {
@@ -393,14 +656,14 @@ var (
 				// log.Println("/pixelmon say")
 
 				// Check if server is online
-				isOnline, _, err := mcstatus.GetMCStatus()
+				status, err := pixelmon.GetServiceStatus(ctx, srv)
 				if err != nil {
 					log.Printf("Error: %v", err)
 
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Err_Status],
+							Content: getMessage(i, "status.error", nil),
 							Flags:   64,
 						},
 					})
@@ -410,11 +673,11 @@ var (
 
 					return
 				}
-				if !isOnline {
+				if !status.Online {
 					err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 						Type: discordgo.InteractionResponseChannelMessageWithSource,
 						Data: &discordgo.InteractionResponseData{
-							Content: pixelmon.Message[pixelmon.Offline],
+							Content: getMessage(i, "status.offline", nil),
 							Flags:   64,
 						},
 					})
@@ -446,7 +709,7 @@ var (
 				err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 					Type: discordgo.InteractionResponseChannelMessageWithSource,
 					Data: &discordgo.InteractionResponseData{
-						Content: pixelmon.Message[pixelmon.SendingMessage] + "`" + message + "`",
+						Content: getMessage(i, "say.sending", map[string]any{"Message": message}),
 					},
 				})
 				if err != nil {
@@ -454,11 +717,11 @@ var (
 				}
 
 				// Send message
-				if err := pixelmon.SendMessage(message); err != nil {
+				if err := pixelmon.SendMessage(ctx, srv, message); err != nil {
 					log.Printf("Error: %v", err)
 
 					_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-						Content: pixelmon.Message[pixelmon.Err_SendingMessage],
+						Content: getMessage(i, "say.error", map[string]any{"Message": message}),
 					})
 					if err != nil {
 						log.Fatalf("Error sending follow-up message: %v", err)
@@ -468,12 +731,182 @@ var (
 				}
 
 				_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-					Content: pixelmon.Message[pixelmon.Success_SendingMessage] + "`" + message + "`",
+					Content: getMessage(i, "say.success", map[string]any{"Message": message}),
 				})
 				if err != nil {
 					log.Fatalf("Error sending follow-up message: %v", err)
 				}
 			}
 		},
+		"seigetsu": func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			// Check if user is an admin
+			if !isAdmin(i) {
+				err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+					Type: discordgo.InteractionResponseChannelMessageWithSource,
+					Data: &discordgo.InteractionResponseData{
+						Content: getMessage(i, "error.not_allowed", nil),
+						Flags:   discordgo.MessageFlagsEphemeral,
+					},
+				})
+				if err != nil {
+					log.Printf("Error: %v", err)
+				}
+
+				return
+			}
+
+			switch i.ApplicationCommandData().Options[0].Name {
+			case "config":
+				handleConfig(s, i)
+			}
+		},
+	}
+
+	// ComponentHandlers are keyed by the custom ID of the component up to the first ":"
+	ComponentHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+		confirmStopID:   confirmStop,
+		cancelStopID:    cancelStop,
+		vetoStopID:      vetoStop,
+		statusStartID:   statusStart,
+		statusStopID:    statusStop,
+		statusRefreshID: statusRefresh,
 	}
 )
+
+func init() {
+	for _, command := range Commands {
+		localizeCommand(command)
+	}
+}
+
+// handleStart starts the server's instance and Pixelmon service
+func handleStart(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	content := getMessage(i, "start.starting", nil)
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	ctx, done := startOperation()
+	defer done()
+	ctx = withProgress(ctx, getLocale(i), content, editResponse(s, i))
+
+	// Start Pixelmon instance and service, rolling back if it fails partway
+	if err := pixelmon.StartServer(ctx, srv); err != nil {
+		log.Printf("Error: %v", err)
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: failureMessage(i, "start.error", err),
+		})
+		if err != nil {
+			log.Fatalf("Error sending follow-up message: %v", err)
+		}
+
+		return
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: getMessage(i, "status.online", nil),
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
+
+// handleStop stops the server's Pixelmon service and instance, asking for confirmation if players are online
+func handleStop(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	// Check if a stop is already scheduled
+	if isStopScheduled(srv) {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: getMessage(i, "stop.already_scheduled", nil),
+				Flags:   64,
+			},
+		})
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		return
+	}
+
+	// Looking up the players can take longer than Discord waits for a response. Only the user sees the response,
+	// since it may ask them to confirm, and the stop itself is posted in a follow-up everyone sees.
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	// Ask for confirmation if players are online, or if it's unknown whether they are
+	ctx, cancel := requestContext()
+	players, err := pixelmon.GetPlayerNames(ctx, srv)
+	cancel()
+	confirm := ""
+	if err != nil {
+		log.Printf("Error: %v", err)
+		confirm = getMessage(i, "stop.confirm_unknown", nil)
+	} else if len(players) > 0 {
+		confirm = getMessage(i, "stop.confirm", map[string]any{"Players": formatPlayers(players)})
+	}
+	if confirm != "" {
+		components := confirmStopComponents(i, srv)
+		_, err = s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &confirm,
+			Components: &components,
+		})
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		return
+	}
+
+	// The deferred response is edited first, since Discord would otherwise turn the first follow-up into it
+	content := getMessage(i, "stop.stopping", nil)
+	editResponse(s, i)(content)
+	msg, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: content,
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+	if err := s.InteractionResponseDelete(i.Interaction); err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	// Stop Pixelmon service and instance
+	ctx, done := startOperation()
+	defer done()
+	ctx = withProgress(ctx, getLocale(i), content, editFollowup(s, i, msg.ID))
+
+	if err := stopServer(ctx, srv); err != nil {
+		log.Printf("Error: %v", err)
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: failureMessage(i, "stop.error", err),
+		})
+		if err != nil {
+			log.Fatalf("Error sending follow-up message: %v", err)
+		}
+
+		return
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: getMessage(i, "status.offline", nil),
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+package discord
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+// guildConfig is the configuration of a guild
+type guildConfig struct {
+	// Servers are the Pixelmon servers linked to the guild. The first one is used when no server is chosen.
+	Servers []pixelmon.Server `json:"servers,omitempty"`
+
+	// RequiredRoles replace the roles that subcommands require by default
+	RequiredRoles []string `json:"required_roles,omitempty"`
+
+	// Channel is where the bot announces when a linked server goes online or offline
+	Channel string `json:"channel,omitempty"`
+
+	// Language overrides the guild's Discord locale
+	Language string `json:"language,omitempty"`
+}
+
+// getGuildConfig gets the configuration of the guild
+func getGuildConfig(guildID string) guildConfig {
+	config, _, err := guildConfigs.Get(guildID)
+	if err != nil {
+		log.Printf("Error loading guild config: %v", err)
+	}
+
+	return config
+}
+
+// getGuildConfigs gets the configuration of every guild, keyed by guild ID
+func getGuildConfigs() map[string]guildConfig {
+	configs, err := guildConfigs.All()
+	if err != nil {
+		log.Printf("Error loading guild configs: %v", err)
+	}
+
+	return configs
+}
+
+// updateGuildConfig changes the configuration of the guild and saves it
+func updateGuildConfig(guildID string, update func(config *guildConfig)) error {
+	return guildConfigs.Update(guildID, func(config *guildConfig) error {
+		update(config)
+		return nil
+	})
+}
+
+// getGuildServers gets the servers of the guild, which is the default server if it hasn't linked any. Linked servers
+// are read from the bot's servers, so a guild can't use a server the operator removed or change how one is managed.
+func getGuildServers(guildID string) []pixelmon.Server {
+	var servers []pixelmon.Server
+	for _, linked := range getGuildConfig(guildID).Servers {
+		srv, ok := pixelmon.LookupServer(linked.Name)
+		if !ok {
+			log.Printf("Guild %v links %v, which isn't one of the bot's servers", guildID, linked.Name)
+			continue
+		}
+		servers = append(servers, srv)
+	}
+	if len(servers) > 0 {
+		return servers
+	}
+
+	return []pixelmon.Server{pixelmon.DefaultServer()}
+}
+
+// getServerByName finds the guild's server with the name, or its first server if the name is empty
+func getServerByName(guildID string, name string) (pixelmon.Server, bool) {
+	servers := getGuildServers(guildID)
+	if name == "" {
+		return servers[0], true
+	}
+
+	for _, srv := range servers {
+		if strings.EqualFold(srv.Name, name) {
+			return srv, true
+		}
+	}
+
+	return pixelmon.Server{Name: name}, false
+}
+
+// getServerName gets the server chosen for the interaction from the "server" option of the subcommand, or from the
+// part of a component's custom ID after the first ":"
+func getServerName(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand, discordgo.InteractionApplicationCommandAutocomplete:
+		for _, option := range subcommandOptions(i) {
+			if option.Name == "server" && option.Type == discordgo.ApplicationCommandOptionString {
+				return option.StringValue()
+			}
+		}
+	case discordgo.InteractionMessageComponent:
+		_, name, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+		return name
+	}
+
+	return ""
+}
+
+// getServer gets the server the interaction is about. It is false if the guild has no server with the chosen name.
+func getServer(i *discordgo.InteractionCreate) (pixelmon.Server, bool) {
+	return getServerByName(i.GuildID, getServerName(i))
+}
+
+// checkServer gets the server the interaction is about and responds if the guild doesn't have it
+func checkServer(s *discordgo.Session, i *discordgo.InteractionCreate) (pixelmon.Server, bool) {
+	srv, ok := getServer(i)
+	if ok {
+		return srv, true
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, "config.unknown_server", map[string]any{"Name": srv.Name}),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	return srv, false
+}
+
+// serverOption is the option to choose one of the guild's servers
+func serverOption() *discordgo.ApplicationCommandOption {
+	return &discordgo.ApplicationCommandOption{
+		Type:         discordgo.ApplicationCommandOptionString,
+		Name:         "server",
+		Description:  "Server to use. Defaults to the first linked server",
+		MaxLength:    32,
+		Autocomplete: true,
+	}
+}
+
+// handleConfig views and changes the guild's configuration
+func handleConfig(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	action := i.ApplicationCommandData().Options[0].Options[0]
+
+	values := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, option := range action.Options {
+		values[option.Name] = option
+	}
+	getString := func(name string) string {
+		if option, ok := values[name]; ok {
+			return strings.TrimSpace(option.StringValue())
+		}
+		return ""
+	}
+
+	if action.Name == "view" {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{configEmbed(i)},
+				Flags:  discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		return
+	}
+
+	key := "config.updated"
+	data := map[string]any{"Name": getString("name")}
+	err := updateGuildConfig(i.GuildID, func(config *guildConfig) {
+		switch action.Name {
+		case "link":
+			srv, ok := pixelmon.LookupServer(getString("name"))
+			if !ok {
+				key = "config.not_allowed"
+				return
+			}
+			data["Name"] = srv.Name
+
+			// Linking a server with the same name replaces it
+			for n, linked := range config.Servers {
+				if strings.EqualFold(linked.Name, srv.Name) {
+					config.Servers[n] = srv
+					key = "config.linked"
+					return
+				}
+			}
+			config.Servers = append(config.Servers, srv)
+			key = "config.linked"
+		case "unlink":
+			for n, linked := range config.Servers {
+				if strings.EqualFold(linked.Name, getString("name")) {
+					config.Servers = append(config.Servers[:n], config.Servers[n+1:]...)
+					key = "config.unlinked"
+					return
+				}
+			}
+			key = "config.unknown_server"
+		case "add-role":
+			roleID := values["role"].RoleValue(nil, "").ID
+			for _, id := range config.RequiredRoles {
+				if id == roleID {
+					return
+				}
+			}
+			config.RequiredRoles = append(config.RequiredRoles, roleID)
+		case "remove-role":
+			roleID := values["role"].RoleValue(nil, "").ID
+			var kept []string
+			for _, id := range config.RequiredRoles {
+				if id != roleID {
+					kept = append(kept, id)
+				}
+			}
+			config.RequiredRoles = kept
+		case "channel":
+			config.Channel = ""
+			if option, ok := values["channel"]; ok {
+				config.Channel = option.ChannelValue(nil).ID
+			}
+		case "language":
+			config.Language = getString("language")
+		}
+	})
+	if err != nil {
+		log.Printf("Error saving guild config: %v", err)
+		key = "config.error"
+	}
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, key, data),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
+
+// configEmbed shows the guild's configuration
+func configEmbed(i *discordgo.InteractionCreate) *discordgo.MessageEmbed {
+	config := getGuildConfig(i.GuildID)
+	none := getMessage(i, "config.none", nil)
+
+	var servers []string
+	for _, srv := range getGuildServers(i.GuildID) {
+		instanceID := srv.InstanceID
+		if srv.NameTag != "" {
+			instanceID = "Name=" + srv.NameTag
+		}
+		servers = append(servers, getMessage(i, "config.server", map[string]any{"Name": srv.Name, "Hostname": srv.Address(), "InstanceID": instanceID, "Region": srv.Region}))
+	}
+
+	roles := none
+	if len(config.RequiredRoles) > 0 {
+		roles = formatRuleEntries(config.RequiredRoles, nil, nil)
+	}
+
+	channel := none
+	if config.Channel != "" {
+		channel = "<#" + config.Channel + ">"
+	}
+
+	language := none
+	if config.Language != "" {
+		language = "`" + config.Language + "`"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: getMessage(i, "config.title", nil),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: getMessage(i, "config.servers", nil), Value: strings.Join(servers, "\n")},
+			{Name: getMessage(i, "config.required_roles", nil), Value: roles, Inline: true},
+			{Name: getMessage(i, "config.channel", nil), Value: channel, Inline: true},
+			{Name: getMessage(i, "config.language", nil), Value: language, Inline: true},
+		},
+	}
+}
+
+// languageChoices returns the locales that have messages
+func languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, locale := range messages.Locales() {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: locale, Value: locale})
+	}
+
+	return choices
+}
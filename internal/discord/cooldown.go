@@ -0,0 +1,69 @@
+package discord
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/cooldown"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	defaultUserCooldown     = "10/1m"
+	defaultCommandCooldowns = "start=1/5m,stop=1/5m,restart=1/5m"
+	defaultGlobalCooldowns  = "start=1/2m,stop=1/2m,restart=1/2m"
+)
+
+// Cooldowns is shared by every command handler to rate limit users
+var Cooldowns = newCooldowns()
+
+// newCooldowns creates the limiter from the COOLDOWN_* environment variables, falling back to the defaults
+func newCooldowns() *cooldown.Limiter {
+	user, err := cooldown.ParseRule(getEnv("COOLDOWN_USER", defaultUserCooldown))
+	if err != nil {
+		log.Printf("Error parsing COOLDOWN_USER: %v", err)
+		user, _ = cooldown.ParseRule(defaultUserCooldown)
+	}
+
+	commands, err := cooldown.ParseRules(getEnv("COOLDOWN_COMMANDS", defaultCommandCooldowns))
+	if err != nil {
+		log.Printf("Error parsing COOLDOWN_COMMANDS: %v", err)
+		commands, _ = cooldown.ParseRules(defaultCommandCooldowns)
+	}
+
+	global, err := cooldown.ParseRules(getEnv("COOLDOWN_GLOBAL", defaultGlobalCooldowns))
+	if err != nil {
+		log.Printf("Error parsing COOLDOWN_GLOBAL: %v", err)
+		global, _ = cooldown.ParseRules(defaultGlobalCooldowns)
+	}
+
+	return cooldown.New(user, commands, global)
+}
+
+// checkCooldown checks if the user is allowed to run the subcommand on the server and responds with when they can
+// retry if not. Admins are exempt from cooldowns.
+func checkCooldown(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server, command string) bool {
+	if isAdmin(i) {
+		return true
+	}
+
+	allowed, retryAt := Cooldowns.Allow(getUserID(i), srv.Key(), command, time.Now())
+	if allowed {
+		return true
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, "cooldown", map[string]any{"Command": command, "RetryAt": fmt.Sprintf("<t:%d:R>", retryAt.Unix())}),
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	return false
+}
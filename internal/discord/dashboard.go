@@ -0,0 +1,338 @@
+package discord
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	defaultDashboardInterval = "30s"
+	dashboardMinEditInterval = 10 * time.Second
+)
+
+// dashboard is a status message of one of the guild's servers that is kept up to date in a channel
+type dashboard struct {
+	Server     string    `json:"server,omitempty"`
+	ChannelID  string    `json:"channel_id"`
+	MessageID  string    `json:"message_id"`
+	Locale     string    `json:"locale"`
+	Snapshot   string    `json:"snapshot"`
+	LastChange time.Time `json:"last_change"`
+
+	dirty    bool
+	lastEdit time.Time
+}
+
+var (
+	// dashboards are keyed by guild ID
+	dashboards       = make(map[string]*dashboard)
+	dashboardsMu     sync.Mutex
+	dashboardRefresh = make(chan struct{}, 1)
+	dashboardOnce    sync.Once
+)
+
+// ResumeDashboards loads the stored dashboards and keeps updating them
+func ResumeDashboards(s *discordgo.Session) {
+	stored, err := storedDashboards.All()
+	if err != nil {
+		log.Printf("Error loading dashboards: %v", err)
+	}
+
+	dashboardsMu.Lock()
+	for guildID, d := range stored {
+		dashboards[guildID] = d
+	}
+	for _, d := range dashboards {
+		d.dirty = true
+	}
+	count := len(dashboards)
+	dashboardsMu.Unlock()
+
+	if count > 0 {
+		log.Printf("Resuming %v dashboard(s)", count)
+		startDashboards(s)
+	}
+}
+
+// refreshDashboards asks the dashboard updater to check for changes now instead of waiting for the next poll
+func refreshDashboards() {
+	select {
+	case dashboardRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// handleDashboard posts a new dashboard of the server in the channel, replacing the guild's previous dashboard
+func handleDashboard(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	d := &dashboard{
+		Server:     srv.Name,
+		ChannelID:  i.ChannelID,
+		Locale:     getLocale(i),
+		LastChange: time.Now(),
+	}
+	msg, err := s.ChannelMessageSendEmbed(d.ChannelID, dashboardEmbed(d))
+	if err != nil {
+		log.Printf("Error: %v", err)
+
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: getMessage(i, "dashboard.error", nil),
+				Flags:   64,
+			},
+		})
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		return
+	}
+	d.MessageID = msg.ID
+	d.dirty = true
+
+	dashboardsMu.Lock()
+	old, replaced := dashboards[i.GuildID]
+	dashboards[i.GuildID] = d
+	if err := storedDashboards.Put(i.GuildID, d); err != nil {
+		log.Printf("Error saving dashboards: %v", err)
+	}
+	dashboardsMu.Unlock()
+
+	err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, "dashboard.posted", nil),
+			Flags:   64,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	if replaced {
+		if err := s.ChannelMessageDelete(old.ChannelID, old.MessageID); err != nil {
+			log.Printf("Error deleting old dashboard: %v", err)
+		}
+	}
+
+	startDashboards(s)
+	refreshDashboards()
+}
+
+// startDashboards starts the dashboard updater if it isn't running yet
+func startDashboards(s *discordgo.Session) {
+	dashboardOnce.Do(func() {
+		interval, err := time.ParseDuration(getEnv("DASHBOARD_INTERVAL", defaultDashboardInterval))
+		if err != nil {
+			log.Printf("Error parsing DASHBOARD_INTERVAL: %v", err)
+			interval, _ = time.ParseDuration(defaultDashboardInterval)
+		}
+
+		// Check for changes as soon as a lifecycle operation changes the state
+		pixelmon.OnStateChange(func(pixelmon.Server, pixelmon.State) {
+			refreshDashboards()
+		})
+
+		go runDashboards(s, interval)
+	})
+}
+
+// runDashboards polls the server and edits the dashboards when something changed
+func runDashboards(s *discordgo.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// throttled fires when the edits held back to stay under Discord's rate limits can be made
+	throttled := time.NewTimer(interval)
+	throttled.Stop()
+	defer throttled.Stop()
+
+	for {
+		if wait := updateDashboards(s); wait > 0 {
+			if !throttled.Stop() {
+				select {
+				case <-throttled.C:
+				default:
+				}
+			}
+			throttled.Reset(wait)
+		}
+
+		select {
+		case <-botCtx.Done():
+			return
+		case <-ticker.C:
+		case <-dashboardRefresh:
+		case <-throttled.C:
+		}
+	}
+}
+
+// updateDashboards edits every dashboard that changed, at most once per dashboardMinEditInterval. It returns how long
+// until the edits it held back can be made, or 0 if there are none.
+func updateDashboards(s *discordgo.Session) (next time.Duration) {
+	// The dashboards are copied so the lock isn't held while waiting on the servers and Discord
+	dashboardsMu.Lock()
+	current := make(map[string]*dashboard, len(dashboards))
+	updated := make(map[string]*dashboard, len(dashboards))
+	for guildID, d := range dashboards {
+		current[guildID] = d
+		copied := *d
+		updated[guildID] = &copied
+	}
+	dashboardsMu.Unlock()
+
+	// Dashboards of the same server share a snapshot
+	snapshots := make(map[string]string)
+
+	// changed has the guilds whose dashboard needs to be saved
+	changed := make(map[string]bool)
+	for guildID, d := range updated {
+		srv, ok := getServerByName(guildID, d.Server)
+		if !ok {
+			log.Printf("Dashboard of guild %v shows unlinked server %v", guildID, d.Server)
+			continue
+		}
+		if d.Server == "" {
+			d.Server = srv.Name
+			changed[guildID] = true
+		}
+
+		snapshot, ok := snapshots[srv.Key()]
+		if !ok {
+			if snapshot, ok = getSnapshot(srv); !ok {
+				continue
+			}
+			snapshots[srv.Key()] = snapshot
+		}
+
+		if d.Snapshot != snapshot {
+			d.Snapshot = snapshot
+			d.LastChange = time.Now()
+			d.dirty = true
+			changed[guildID] = true
+		}
+		if !d.dirty {
+			continue
+		}
+
+		// Throttle edits to stay under Discord's rate limits
+		if wait := dashboardMinEditInterval - time.Since(d.lastEdit); wait > 0 {
+			if next == 0 || wait < next {
+				next = wait
+			}
+			continue
+		}
+
+		_, err := s.ChannelMessageEditEmbed(d.ChannelID, d.MessageID, dashboardEmbed(d))
+		if err != nil {
+			log.Printf("Error updating dashboard: %v", err)
+
+			// Stop updating dashboards that were deleted
+			var restErr *discordgo.RESTError
+			if errors.As(err, &restErr) && restErr.Response != nil && restErr.Response.StatusCode == http.StatusNotFound {
+				delete(updated, guildID)
+				changed[guildID] = true
+			}
+
+			continue
+		}
+		d.dirty = false
+		d.lastEdit = time.Now()
+	}
+
+	dashboardsMu.Lock()
+	defer dashboardsMu.Unlock()
+
+	for guildID, d := range current {
+		// Dashboards that were replaced in the meantime are left alone
+		if dashboards[guildID] != d {
+			continue
+		}
+
+		u, ok := updated[guildID]
+		if ok {
+			*d = *u
+		} else {
+			delete(dashboards, guildID)
+		}
+		if !changed[guildID] {
+			continue
+		}
+
+		var err error
+		if ok {
+			err = storedDashboards.Put(guildID, d)
+		} else {
+			err = storedDashboards.Delete(guildID)
+		}
+		if err != nil {
+			log.Printf("Error saving dashboards: %v", err)
+		}
+	}
+
+	return next
+}
+
+// getSnapshot returns the current state and online players of the server as "<state>|<player>,<player>"
+func getSnapshot(srv pixelmon.Server) (string, bool) {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	info, err := pixelmon.GetServerInfo(ctx, srv)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return "", false
+	}
+
+	state := info.State
+	var players []string
+	if info.Status != nil && info.Status.Online {
+		state = "online"
+		for _, player := range info.Status.Players.List {
+			players = append(players, player.NameClean)
+		}
+	}
+
+	return state + "|" + strings.Join(players, ","), true
+}
+
+// dashboardEmbed builds the embed for a dashboard from its snapshot
+func dashboardEmbed(d *dashboard) *discordgo.MessageEmbed {
+	state, players, _ := strings.Cut(d.Snapshot, "|")
+	if state == "" {
+		state = "unknown"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: getMessageIn(d.Locale, "dashboard.title", map[string]any{"Server": d.Server}),
+		Color: colorPending,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: getMessageIn(d.Locale, "embed.state", nil), Value: state, Inline: true},
+			{Name: getMessageIn(d.Locale, "dashboard.last_change", nil), Value: fmt.Sprintf("<t:%d:R>", d.LastChange.Unix()), Inline: true},
+		},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+	switch state {
+	case "online":
+		embed.Color = colorOnline
+	case "stopped":
+		embed.Color = colorOffline
+	}
+
+	value := getMessageIn(d.Locale, "dashboard.no_players", nil)
+	if players != "" {
+		value = formatPlayers(strings.Split(players, ","))
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: getMessageIn(d.Locale, "embed.players", nil), Value: value})
+
+	return embed
+}
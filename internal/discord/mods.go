@@ -0,0 +1,135 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	// maxEmbedDescription and maxEmbedField are the most characters Discord allows in an embed's description and in
+	// a field's value
+	maxEmbedDescription = 4096
+	maxEmbedField       = 1024
+
+	// modsTimeout limits reading the mods, which runs a script on the instance that can take longer than
+	// requestTimeout on a large mods folder
+	modsTimeout = 2 * time.Minute
+)
+
+// handleMods lists the server's mods and posts instructions for setting up a client that can join it
+func handleMods(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, "mods.reading", nil),
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(botCtx, modsTimeout)
+	defer cancel()
+
+	params := &discordgo.WebhookParams{}
+	list, err := pixelmon.GetMods(ctx, srv)
+	switch {
+	case errors.Is(err, pixelmon.ErrOffline):
+		params.Content = getMessage(i, "mods.offline", nil)
+	case err != nil:
+		log.Printf("Error: %v", err)
+		params.Content = failureMessage(i, "mods.error", err)
+	case len(list.Mods) == 0:
+		params.Content = getMessage(i, "mods.none", nil)
+	default:
+		params.Embeds = []*discordgo.MessageEmbed{modsEmbed(i, list), clientEmbed(i, srv, list)}
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, params)
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
+
+// modsEmbed lists every mod of the server
+func modsEmbed(i *discordgo.InteractionCreate, list pixelmon.ModList) *discordgo.MessageEmbed {
+	lines := make([]string, 0, len(list.Mods))
+	for _, mod := range list.Mods {
+		lines = append(lines, modLine(i, mod))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       getMessage(i, "mods.title", map[string]any{"Count": len(list.Mods)}),
+		Color:       colorOnline,
+		Description: joinLines(i, lines, maxEmbedDescription),
+	}
+}
+
+// clientEmbed tells players which Forge version and mods to install to join the server
+func clientEmbed(i *discordgo.InteractionCreate, srv pixelmon.Server, list pixelmon.ModList) *discordgo.MessageEmbed {
+	unknown := getMessage(i, "mods.unknown", nil)
+
+	forge, minecraft := list.Forge, list.Minecraft
+	if forge == "" {
+		forge, minecraft = unknown, unknown
+	}
+	pixelmonVersion := unknown
+	if mod, ok := list.Pixelmon(); ok && mod.Version != "" {
+		pixelmonVersion = mod.Version
+	}
+
+	clientMods := list.ClientMods()
+	lines := make([]string, 0, len(clientMods))
+	for _, mod := range clientMods {
+		lines = append(lines, modLine(i, mod))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, getMessage(i, "mods.client_none", nil))
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       getMessage(i, "mods.client_title", nil),
+		Color:       colorPending,
+		Description: getMessage(i, "mods.client_steps", map[string]any{"Forge": forge, "Minecraft": minecraft, "Address": srv.Address()}),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: getMessage(i, "mods.minecraft", nil), Value: minecraft, Inline: true},
+			{Name: getMessage(i, "mods.forge", nil), Value: forge, Inline: true},
+			{Name: getMessage(i, "mods.pixelmon", nil), Value: pixelmonVersion, Inline: true},
+			{Name: getMessage(i, "mods.client_mods", map[string]any{"Count": len(clientMods)}), Value: joinLines(i, lines, maxEmbedField)},
+		},
+	}
+}
+
+// modLine describes the mod in a list
+func modLine(i *discordgo.InteractionCreate, mod pixelmon.Mod) string {
+	version := mod.Version
+	if version == "" {
+		version = getMessage(i, "mods.unknown", nil)
+	}
+
+	return getMessage(i, "mods.mod", map[string]any{"Name": mod.Name, "Version": version, "File": mod.File})
+}
+
+// joinLines joins as many lines as fit in limit characters, saying how many were left out
+func joinLines(i *discordgo.InteractionCreate, lines []string, limit int) string {
+	if text := strings.Join(lines, "\n"); len(text) <= limit {
+		return text
+	}
+
+	// Leave room to say how many lines were left out
+	room := limit - len(getMessage(i, "mods.more", map[string]any{"Count": len(lines)}))
+	n, size := 0, 0
+	for n < len(lines) && size+len(lines[n])+1 <= room {
+		size += len(lines[n]) + 1
+		n++
+	}
+
+	kept := append(lines[:n:n], getMessage(i, "mods.more", map[string]any{"Count": len(lines) - n}))
+	return strings.Join(kept, "\n")
+}
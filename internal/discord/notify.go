@@ -0,0 +1,73 @@
+package discord
+
+import (
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+// StartNotifications announces in each guild's notification channel when one of its servers goes online or offline
+func StartNotifications(s *discordgo.Session) {
+	pixelmon.OnStateChange(func(srv pixelmon.Server, state pixelmon.State) {
+		key := ""
+		switch state {
+		case pixelmon.StateOnline:
+			key = "status.online"
+		case pixelmon.StateOffline:
+			key = "status.offline"
+		default:
+			return
+		}
+
+		go notify(s, srv, key, nil)
+	})
+}
+
+// notify sends the message to the notification channel of every guild that has the server. The server's name in the
+// guild is added to data.
+func notify(s *discordgo.Session, srv pixelmon.Server, key string, data map[string]any) {
+	for guildID, config := range getGuildConfigs() {
+		if config.Channel == "" {
+			continue
+		}
+
+		for _, linked := range getGuildServers(guildID) {
+			if linked.Key() != srv.Key() {
+				continue
+			}
+
+			locale := config.Language
+			if locale == "" {
+				locale = catalog.DefaultLocale
+			}
+			values := map[string]any{"Server": linked.Name}
+			for k, v := range data {
+				values[k] = v
+			}
+			if _, err := s.ChannelMessageSend(config.Channel, getMessageIn(locale, key, values)); err != nil {
+				log.Printf("Error sending notification: %v", err)
+			}
+
+			break
+		}
+	}
+}
+
+// watchedServers returns the default server and every linked server once
+func watchedServers() []pixelmon.Server {
+	servers := []pixelmon.Server{pixelmon.DefaultServer()}
+	seen := map[string]bool{servers[0].Key(): true}
+	for guildID := range getGuildConfigs() {
+		for _, srv := range getGuildServers(guildID) {
+			if seen[srv.Key()] {
+				continue
+			}
+			seen[srv.Key()] = true
+			servers = append(servers, srv)
+		}
+	}
+
+	return servers
+}
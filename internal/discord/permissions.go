@@ -0,0 +1,296 @@
+package discord
+
+import (
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/permissions"
+)
+
+// getPolicy gets the permission policy of the guild. Subcommands that require roles by default require the guild's
+// required roles instead, if it has any.
+func getPolicy(guildID string) permissions.Policy {
+	policy, _, err := policies.Get(guildID)
+	if err != nil {
+		log.Printf("Error loading permissions: %v", err)
+	}
+	if policy == nil {
+		policy = make(permissions.Policy)
+	}
+
+	if requiredRoles := getGuildConfig(guildID).RequiredRoles; len(requiredRoles) > 0 {
+		for subcommand, rule := range permissions.Default() {
+			if _, ok := policy[subcommand]; !ok && len(rule.Roles) > 0 {
+				rule.Roles = requiredRoles
+				policy[subcommand] = rule
+			}
+		}
+	}
+
+	return policy
+}
+
+// setRule changes the rule of a subcommand in the guild's policy and saves it
+func setRule(guildID string, subcommand string, rule *permissions.Rule) error {
+	return policies.Update(guildID, func(policy *permissions.Policy) error {
+		if *policy == nil {
+			*policy = make(permissions.Policy)
+		}
+		if rule == nil {
+			delete(*policy, subcommand)
+		} else {
+			(*policy)[subcommand] = *rule
+		}
+
+		return nil
+	})
+}
+
+// getMember gets the user's ID, the IDs and names of their roles and their permissions
+func getMember(s *discordgo.Session, i *discordgo.InteractionCreate, withRoleNames bool) permissions.Member {
+	member := permissions.Member{UserID: getUserID(i)}
+	if i.Member == nil {
+		return member
+	}
+
+	member.Permissions = i.Member.Permissions
+	member.Roles = append(member.Roles, i.Member.Roles...)
+	if !withRoleNames {
+		return member
+	}
+
+	// Look up the names of the roles in the state cache, only fetching the guild's roles if one is missing
+	var missing bool
+	for _, roleID := range i.Member.Roles {
+		role, err := s.State.Role(i.GuildID, roleID)
+		if err != nil {
+			missing = true
+			break
+		}
+		member.Roles = append(member.Roles, role.Name)
+	}
+	if !missing {
+		return member
+	}
+
+	roles, err := s.GuildRoles(i.GuildID)
+	if err != nil {
+		log.Printf("Error getting guild roles: %v", err)
+		return member
+	}
+	member.Roles = append(member.Roles[:0], i.Member.Roles...)
+	for _, role := range roles {
+		for _, roleID := range i.Member.Roles {
+			if role.ID == roleID {
+				member.Roles = append(member.Roles, role.Name)
+			}
+		}
+	}
+
+	return member
+}
+
+// isAllowed checks if the user can use the subcommand. Admins can use every subcommand and are the only ones who
+// can change the permissions.
+func isAllowed(s *discordgo.Session, i *discordgo.InteractionCreate, subcommand string) bool {
+	if isAdmin(i) {
+		return true
+	}
+	if subcommand == "permissions" {
+		return false
+	}
+
+	rule := getPolicy(i.GuildID).Rule(subcommand)
+	withRoleNames := len(rule.Roles) > 0 || len(rule.DenyRoles) > 0
+
+	return rule.Allows(getMember(s, i, withRoleNames))
+}
+
+// checkPermission checks if the user can use the subcommand and responds if they can't
+func checkPermission(s *discordgo.Session, i *discordgo.InteractionCreate, subcommand string) bool {
+	if isAllowed(s, i, subcommand) {
+		return true
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, "error.not_allowed", nil),
+			Flags:   64,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	return false
+}
+
+// handlePermissions views and changes the guild's permission policy
+func handlePermissions(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	action := i.ApplicationCommandData().Options[0].Options[0]
+
+	var subcommand, role, user, permission string
+	for _, option := range action.Options {
+		switch option.Name {
+		case "subcommand":
+			subcommand = option.StringValue()
+		case "role":
+			role = option.RoleValue(nil, "").ID
+		case "user":
+			user = option.UserValue(nil).ID
+		case "permission":
+			permission = option.StringValue()
+		}
+	}
+
+	if action.Name == "view" {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{policyEmbed(i, subcommand)},
+				Flags:  64,
+			},
+		})
+		if err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		return
+	}
+
+	var roles, users, perms []string
+	if role != "" {
+		roles = append(roles, role)
+	}
+	if user != "" {
+		users = append(users, user)
+	}
+	if permission != "" {
+		perms = append(perms, permission)
+	}
+
+	rule := getPolicy(i.GuildID).Rule(subcommand)
+	key := "permissions.updated"
+	switch action.Name {
+	case "allow":
+		rule = rule.Allow(roles, users, perms)
+	case "deny":
+		rule = rule.Deny(roles, users)
+	case "remove":
+		rule = rule.Remove(roles, users, perms)
+	case "reset":
+		key = "permissions.reset"
+	}
+
+	if action.Name != "reset" && len(roles)+len(users)+len(perms) == 0 {
+		key = "permissions.missing_target"
+	} else {
+		var err error
+		if action.Name == "reset" {
+			err = setRule(i.GuildID, subcommand, nil)
+		} else {
+			err = setRule(i.GuildID, subcommand, &rule)
+		}
+		if err != nil {
+			log.Printf("Error saving permissions: %v", err)
+			key = "permissions.error"
+		}
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: getMessage(i, key, map[string]any{"Subcommand": subcommand}),
+			Flags:   64,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
+
+// policyEmbed shows the rule of the subcommand, or of every subcommand if it is empty
+func policyEmbed(i *discordgo.InteractionCreate, subcommand string) *discordgo.MessageEmbed {
+	policy := getPolicy(i.GuildID)
+
+	subcommands := []string{subcommand}
+	if subcommand == "" {
+		subcommands = policy.Subcommands()
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       getMessage(i, "permissions.title", nil),
+		Description: getMessage(i, "permissions.description", nil),
+	}
+	for _, name := range subcommands {
+		rule := policy.Rule(name)
+
+		var lines []string
+		if allowed := formatRuleEntries(rule.Roles, rule.Users, rule.Permissions); allowed != "" {
+			lines = append(lines, getMessage(i, "permissions.allow", map[string]any{"Entries": allowed}))
+		}
+		if denied := formatRuleEntries(rule.DenyRoles, rule.DenyUsers, nil); denied != "" {
+			lines = append(lines, getMessage(i, "permissions.deny", map[string]any{"Entries": denied}))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, getMessage(i, "permissions.everyone", nil))
+		}
+
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  "/pixelmon " + name,
+			Value: strings.Join(lines, "\n"),
+		})
+	}
+
+	return embed
+}
+
+// formatRuleEntries formats roles, users and permissions as mentions
+func formatRuleEntries(roles []string, users []string, perms []string) string {
+	var entries []string
+	for _, role := range roles {
+		if _, err := strconv.ParseUint(role, 10, 64); err == nil {
+			entries = append(entries, "<@&"+role+">")
+		} else {
+			entries = append(entries, "`@"+role+"`")
+		}
+	}
+	for _, user := range users {
+		entries = append(entries, "<@"+user+">")
+	}
+	for _, permission := range perms {
+		entries = append(entries, "`"+permission+"`")
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+// permissionChoices returns the Discord permissions that rules can require
+func permissionChoices() []*discordgo.ApplicationCommandOptionChoice {
+	names := make([]string, 0, len(permissions.Names))
+	for name := range permissions.Names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(names))
+	for _, name := range names {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+
+	return choices
+}
+
+// subcommandChoices returns the /pixelmon subcommands that have permissions
+func subcommandChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, name := range []string{"status", "start", "stop", "restart", "dashboard", "whitelist", "online", "say", "resize", "properties", "gamerule", "mods"} {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: name})
+	}
+
+	return choices
+}
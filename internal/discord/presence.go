@@ -0,0 +1,170 @@
+package discord
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const defaultPresenceInterval = "1m"
+
+// presence is the bot's current Discord presence
+type presence struct {
+	status string
+	text   string
+	online bool
+}
+
+var (
+	currentPresence   presence
+	currentPresenceMu sync.Mutex
+)
+
+// StartPresence keeps the bot's presence in sync with the state of the default Pixelmon server
+func StartPresence(s *discordgo.Session) {
+	interval, err := time.ParseDuration(getEnv("PRESENCE_INTERVAL", defaultPresenceInterval))
+	if err != nil {
+		log.Printf("Error parsing PRESENCE_INTERVAL: %v", err)
+		interval, _ = time.ParseDuration(defaultPresenceInterval)
+	}
+
+	states := make(chan pixelmon.State, 8)
+	pixelmon.OnStateChange(func(srv pixelmon.Server, state pixelmon.State) {
+		if srv.Key() != pixelmon.DefaultServer().Key() {
+			return
+		}
+
+		select {
+		case states <- state:
+		default:
+			log.Println("Dropped presence state change")
+		}
+	})
+
+	// Discord resets the presence when the session reconnects
+	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
+		currentPresenceMu.Lock()
+		p := currentPresence
+		currentPresenceMu.Unlock()
+
+		if p.text != "" {
+			updatePresence(s, p, true)
+		}
+	})
+
+	go runPresence(s, interval, states)
+}
+
+// runPresence polls the server and applies lifecycle state changes as they happen
+func runPresence(s *discordgo.Session, interval time.Duration, states <-chan pixelmon.State) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// A lifecycle operation is in progress, so polling would show a stale state
+	busy := false
+
+	updatePresence(s, pollPresence(), false)
+	for {
+		select {
+		case <-botCtx.Done():
+			return
+		case <-ticker.C:
+			if busy {
+				continue
+			}
+		case state := <-states:
+			switch state {
+			case pixelmon.StateStarting:
+				busy = true
+				updatePresence(s, presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.starting", nil)}, false)
+				continue
+			case pixelmon.StateStopping:
+				busy = true
+				updatePresence(s, presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.stopping", nil)}, false)
+				continue
+			case pixelmon.StateRestarting:
+				busy = true
+				updatePresence(s, presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.restarting", nil)}, false)
+				continue
+			case pixelmon.StateOffline:
+				busy = false
+				updatePresence(s, presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.offline", nil)}, false)
+				continue
+			default:
+				busy = false
+			}
+		}
+
+		updatePresence(s, pollPresence(), false)
+	}
+}
+
+// pollPresence checks the instance and the Minecraft service of the default server to get the presence
+func pollPresence() presence {
+	srv := pixelmon.DefaultServer()
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	isRunning, err := pixelmon.GetStatus(ctx, srv)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.unknown", nil)}
+	}
+	if !isRunning {
+		return presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.offline", nil)}
+	}
+
+	status, err := pixelmon.GetServiceStatus(ctx, srv)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.unknown", nil)}
+	}
+	if !status.Online {
+		return presence{status: "idle", text: getMessageIn(catalog.DefaultLocale, "presence.service_offline", nil)}
+	}
+
+	return presence{
+		status: "online",
+		text:   getMessageIn(catalog.DefaultLocale, "presence.online", map[string]any{"Online": status.Players.Online, "Max": status.Players.Max}),
+		online: true,
+	}
+}
+
+// updatePresence sets the bot's presence if it changed or force is set
+func updatePresence(s *discordgo.Session, p presence, force bool) {
+	currentPresenceMu.Lock()
+	defer currentPresenceMu.Unlock()
+
+	if p == currentPresence && !force {
+		return
+	}
+
+	// Show "Playing ..." while online and a custom status otherwise
+	activity := &discordgo.Activity{
+		Name: p.text,
+		Type: discordgo.ActivityTypeGame,
+	}
+	if !p.online {
+		activity = &discordgo.Activity{
+			Name:  "Custom Status",
+			Type:  discordgo.ActivityTypeCustom,
+			State: p.text,
+		}
+	}
+
+	err := s.UpdateStatusComplex(discordgo.UpdateStatusData{
+		Activities: []*discordgo.Activity{activity},
+		Status:     p.status,
+	})
+	if err != nil {
+		log.Printf("Error updating presence: %v", err)
+		return
+	}
+
+	currentPresence = p
+}
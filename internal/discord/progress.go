@@ -0,0 +1,136 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+	"github.com/kn-lim/seigetsu-bot/internal/retry"
+)
+
+// withProgress returns a context that shows the progress of the operation under content through edit
+func withProgress(ctx context.Context, locale string, content string, edit func(content string)) context.Context {
+	return pixelmon.WithProgress(ctx, func(p pixelmon.Progress) {
+		edit(content + "\n" + progressMessage(locale, p))
+	})
+}
+
+// editResponse returns a function that replaces the content of the interaction's response
+func editResponse(s *discordgo.Session, i *discordgo.InteractionCreate) func(content string) {
+	return func(content string) {
+		if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &content}); err != nil {
+			log.Printf("Error: %v", err)
+		}
+	}
+}
+
+// editFollowup returns a function that replaces the content of a follow-up message of the interaction
+func editFollowup(s *discordgo.Session, i *discordgo.InteractionCreate, messageID string) func(content string) {
+	return func(content string) {
+		if _, err := s.FollowupMessageEdit(i.Interaction, messageID, &discordgo.WebhookEdit{Content: &content}); err != nil {
+			log.Printf("Error: %v", err)
+		}
+	}
+}
+
+// progressMessage describes the progress of an operation
+func progressMessage(locale string, p pixelmon.Progress) string {
+	if p.RollingBack {
+		return getMessageIn(locale, "progress.rolling_back", map[string]any{"Server": p.Server.Name, "Stage": stageName(locale, p.Stage)})
+	}
+	if p.DNS != nil {
+		return dnsMessage(locale, p)
+	}
+	if p.Retry == nil {
+		return getMessageIn(locale, "progress.stage", map[string]any{"Server": p.Server.Name, "Stage": stageName(locale, p.Stage)})
+	}
+
+	return getMessageIn(locale, "progress.retrying", map[string]any{
+		"Server":   p.Server.Name,
+		"Step":     stepName(locale, p.Step),
+		"Attempt":  p.Retry.Number,
+		"Attempts": p.Attempts,
+		"Wait":     messages.Duration(locale, max(p.Retry.Wait.Round(time.Second), time.Second)),
+		"Error":    p.Retry.Err,
+	})
+}
+
+// dnsMessage describes the state of the server's DNS record while waiting for it
+func dnsMessage(locale string, p pixelmon.Progress) string {
+	if p.DNS.Addresses == nil && p.DNS.IP == "" {
+		return getMessageIn(locale, "progress.dns_change", map[string]any{"Server": p.Server.Name, "Status": p.DNS.Change})
+	}
+
+	addresses := getMessageIn(locale, "config.none", nil)
+	if len(p.DNS.Addresses) > 0 {
+		addresses = strings.Join(p.DNS.Addresses, ", ")
+	}
+
+	return getMessageIn(locale, "progress.dns_resolve", map[string]any{
+		"Server":    p.Server.Name,
+		"Hostname":  p.Server.Hostname(),
+		"Addresses": addresses,
+		"IP":        p.DNS.IP,
+	})
+}
+
+// failureMessage returns the message for an operation that failed with err, including the step that failed
+func failureMessage(i *discordgo.InteractionCreate, key string, err error) string {
+	srv, _ := getServer(i)
+	return failureMessageIn(getLocale(i), key, err, map[string]any{"User": "<@" + getUserID(i) + ">", "Server": srv.Name})
+}
+
+// failureMessageIn is failureMessage in the locale
+func failureMessageIn(locale string, key string, err error, data map[string]any) string {
+	text := getMessageIn(locale, errorKey(key, err), data)
+
+	// Say which stage failed and what was rolled back
+	var seqErr *pixelmon.SequenceError
+	if errors.As(err, &seqErr) {
+		text += "\n" + getMessageIn(locale, "rollback.failed_stage", map[string]any{"Stage": stageName(locale, seqErr.Stage)})
+
+		if len(seqErr.Undone) > 0 {
+			names := make([]string, 0, len(seqErr.Undone))
+			for _, stage := range seqErr.Undone {
+				names = append(names, stageName(locale, stage))
+			}
+			text += "\n" + getMessageIn(locale, "rollback.undone", map[string]any{"Stages": strings.Join(names, ", ")})
+		}
+		for stage, err := range seqErr.NotUndone {
+			text += "\n" + getMessageIn(locale, "rollback.not_undone", map[string]any{"Stage": stageName(locale, stage), "Error": err})
+		}
+	}
+
+	var stepErr *pixelmon.StepError
+	if !errors.As(err, &stepErr) || errors.Is(err, context.Canceled) {
+		return text
+	}
+
+	attempts := 1
+	cause := stepErr.Err
+	var retryErr *retry.Error
+	if errors.As(err, &retryErr) {
+		attempts = retryErr.Attempts
+		cause = retryErr.Err
+	}
+
+	return text + "\n" + getMessageIn(locale, "progress.failed", map[string]any{
+		"Step":     stepName(locale, stepErr.Step),
+		"Attempts": attempts,
+		"Error":    cause,
+	})
+}
+
+// stageName returns the localized name of the stage
+func stageName(locale string, stage pixelmon.Stage) string {
+	return getMessageIn(locale, "stage."+string(stage), nil)
+}
+
+// stepName returns the localized name of the step
+func stepName(locale string, step pixelmon.Step) string {
+	return getMessageIn(locale, "step."+string(step), nil)
+}
@@ -0,0 +1,209 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+// handleProperties shows or changes a key of the server's server.properties
+func handleProperties(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	action := i.ApplicationCommandData().Options[0].Options[0]
+
+	var key, value string
+	for _, option := range action.Options {
+		switch option.Name {
+		case "key":
+			key = option.StringValue()
+		case "value":
+			value = option.StringValue()
+		}
+	}
+	data := map[string]any{"Key": key, "Value": value}
+
+	// Check the key and value before asking the server
+	property, ok := pixelmon.LookupProperty(key)
+	if !ok {
+		respondEphemeral(s, i, getMessage(i, "properties.unknown", data))
+		return
+	}
+	if action.Name == "set" {
+		if err := property.Validate(value); err != nil {
+			data["Expected"] = expectedMessage(i, property.Schema)
+			respondEphemeral(s, i, getMessage(i, "properties.invalid", data))
+			return
+		}
+	}
+
+	content := getMessage(i, "properties.reading", data)
+	if action.Name == "set" {
+		content = getMessage(i, "properties.setting", data)
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	var text string
+	switch action.Name {
+	case "get":
+		value, found, err := pixelmon.GetProperty(ctx, srv, key)
+		switch {
+		case err != nil:
+			log.Printf("Error: %v", err)
+			text = propertiesFailure(i, err)
+		case !found:
+			text = getMessage(i, "properties.unset", data)
+		default:
+			data["Value"] = value
+			text = getMessage(i, "properties.value", data)
+		}
+	case "set":
+		restart, err := pixelmon.SetProperty(ctx, srv, key, value)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			text = propertiesFailure(i, err)
+			break
+		}
+
+		text = getMessage(i, "properties.success", data)
+		if restart {
+			text += "\n" + getMessage(i, "properties.restart", nil)
+		}
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: text,
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
+
+// propertiesFailure describes why the server.properties couldn't be read or changed
+func propertiesFailure(i *discordgo.InteractionCreate, err error) string {
+	if errors.Is(err, pixelmon.ErrOffline) {
+		return getMessage(i, "properties.offline", nil)
+	}
+
+	return failureMessage(i, "properties.error", err)
+}
+
+// handleGameRule shows or changes a game rule of the running server
+func handleGameRule(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	var rule, value string
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		switch option.Name {
+		case "rule":
+			rule = option.StringValue()
+		case "value":
+			value = option.StringValue()
+		}
+	}
+	data := map[string]any{"Rule": rule, "Value": value}
+
+	// Without a value the rule is only shown
+	show := value == ""
+
+	// Check the rule and value before asking the server
+	schema, ok := pixelmon.LookupGameRule(rule)
+	if !ok {
+		respondEphemeral(s, i, getMessage(i, "gamerule.unknown", data))
+		return
+	}
+	if !show {
+		if err := schema.Validate(value); err != nil {
+			data["Expected"] = expectedMessage(i, schema)
+			respondEphemeral(s, i, getMessage(i, "gamerule.invalid", data))
+			return
+		}
+	}
+
+	content := getMessage(i, "gamerule.setting", data)
+	if show {
+		content = getMessage(i, "gamerule.reading", data)
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	var text string
+	if show {
+		value, err = pixelmon.GetGameRule(ctx, srv, rule)
+		data["Value"] = value
+	} else {
+		err = pixelmon.SetGameRule(ctx, srv, rule, value)
+	}
+	switch {
+	case errors.Is(err, pixelmon.ErrOffline):
+		text = getMessage(i, "status.offline", nil)
+	case errors.Is(err, pixelmon.ErrNotSupported):
+		text = getMessage(i, "gamerule.not_supported", data)
+	case err != nil:
+		log.Printf("Error: %v", err)
+		text = failureMessage(i, "gamerule.error", err)
+	case show:
+		text = getMessage(i, "gamerule.value", data)
+	default:
+		text = getMessage(i, "gamerule.success", data)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: text,
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
+
+// expectedMessage describes the values the schema accepts
+func expectedMessage(i *discordgo.InteractionCreate, schema pixelmon.Schema) string {
+	switch schema.Kind {
+	case pixelmon.KindBool:
+		return getMessage(i, "value.bool", nil)
+	case pixelmon.KindInt:
+		if schema.Max == math.MaxInt32 {
+			return getMessage(i, "value.int_min", map[string]any{"Min": schema.Min})
+		}
+		return getMessage(i, "value.int", map[string]any{"Min": schema.Min, "Max": schema.Max})
+	case pixelmon.KindEnum:
+		return getMessage(i, "value.enum", map[string]any{"Values": "`" + strings.Join(schema.Values, "`, `") + "`"})
+	}
+
+	return getMessage(i, "value.string", nil)
+}
+
+// respondEphemeral responds to the interaction with a message only the user sees
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
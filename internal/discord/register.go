@@ -0,0 +1,127 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RegisterCommands makes the registered commands match Commands, globally if guildID is empty or in the guild
+// otherwise. The commands are only overwritten when they changed, so they never disappear for users.
+func RegisterCommands(s *discordgo.Session, guildID string) error {
+	scope := "global"
+	if guildID != "" {
+		scope = "guild " + guildID
+	}
+
+	registered, err := s.ApplicationCommands(s.State.User.ID, guildID)
+	if err != nil {
+		return fmt.Errorf("failed to get %v commands: %w", scope, err)
+	}
+
+	equal, err := commandsEqual(Commands, registered)
+	if err != nil {
+		return fmt.Errorf("failed to compare %v commands: %w", scope, err)
+	}
+	if equal {
+		log.Printf("Commands of %v are up to date", scope)
+		return nil
+	}
+
+	log.Printf("Updating commands of %v...", scope)
+	if _, err := s.ApplicationCommandBulkOverwrite(s.State.User.ID, guildID, Commands); err != nil {
+		return fmt.Errorf("failed to overwrite %v commands: %w", scope, err)
+	}
+	log.Printf("Updated %v command(s) of %v", len(Commands), scope)
+
+	return nil
+}
+
+// commandsEqual checks if two sets of commands are the same, ignoring their order and the fields Discord fills in
+func commandsEqual(a []*discordgo.ApplicationCommand, b []*discordgo.ApplicationCommand) (bool, error) {
+	normalizedA, err := normalizeCommands(a)
+	if err != nil {
+		return false, err
+	}
+	normalizedB, err := normalizeCommands(b)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(normalizedA, normalizedB), nil
+}
+
+// normalizeCommands converts commands to their JSON form keyed by name, without the fields that Discord assigns and
+// the values that are the same as leaving them out
+func normalizeCommands(commands []*discordgo.ApplicationCommand) (map[string]any, error) {
+	normalized := make(map[string]any, len(commands))
+	for _, command := range commands {
+		b, err := json.Marshal(command)
+		if err != nil {
+			return nil, err
+		}
+
+		var v map[string]any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		// DM permission defaults to true, so only turning it off is a difference that prune would otherwise drop
+		denyDM := v["dm_permission"] == false
+		for _, key := range []string{"id", "application_id", "guild_id", "version", "default_permission", "dm_permission"} {
+			delete(v, key)
+		}
+		if _, ok := v["type"]; !ok {
+			v["type"] = float64(discordgo.ChatApplicationCommand)
+		}
+
+		v = prune(v).(map[string]any)
+		if denyDM {
+			v["dm_permission"] = false
+		}
+		normalized[command.Name] = v
+	}
+
+	return normalized, nil
+}
+
+// prune removes null, false, empty and zero-length values from decoded JSON
+func prune(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			value = prune(value)
+			if isEmpty(value) {
+				delete(v, key)
+				continue
+			}
+			v[key] = value
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = prune(value)
+		}
+	}
+
+	return v
+}
+
+// isEmpty checks if a decoded JSON value is the same as leaving it out
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case string:
+		return v == ""
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	}
+
+	return false
+}
@@ -0,0 +1,205 @@
+package discord
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestCommandsEqual(t *testing.T) {
+	yes, no := true, false
+	admin := int64(discordgo.PermissionAdministrator)
+	manage := int64(discordgo.PermissionManageServer)
+
+	// command returns a /pixelmon command with a server option, which the changes modify
+	command := func(changes ...func(*discordgo.ApplicationCommand)) *discordgo.ApplicationCommand {
+		c := &discordgo.ApplicationCommand{
+			Name:        "pixelmon",
+			Description: "Manage the Pixelmon server",
+			Options: []*discordgo.ApplicationCommandOption{{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "server",
+				Description: "The server",
+			}},
+		}
+		for _, change := range changes {
+			change(c)
+		}
+		return c
+	}
+
+	// registered returns the command like Discord returns it, with the fields it assigns
+	registered := func(changes ...func(*discordgo.ApplicationCommand)) *discordgo.ApplicationCommand {
+		c := command(changes...)
+		c.ID = "1"
+		c.ApplicationID = "2"
+		c.Version = "3"
+		c.Type = discordgo.ChatApplicationCommand
+		if c.DMPermission == nil {
+			c.DMPermission = &yes
+		}
+		return c
+	}
+
+	tests := []struct {
+		name string
+		a    []*discordgo.ApplicationCommand
+		b    []*discordgo.ApplicationCommand
+		want bool
+	}{
+		{
+			name: "same",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b:    []*discordgo.ApplicationCommand{command()},
+			want: true,
+		},
+		{
+			name: "assigned fields",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b:    []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) { c.GuildID = "4" })},
+			want: true,
+		},
+		{
+			name: "order",
+			a:    []*discordgo.ApplicationCommand{command(), {Name: "seigetsu", Description: "Configure the bot"}},
+			b:    []*discordgo.ApplicationCommand{{Name: "seigetsu", Description: "Configure the bot"}, command()},
+			want: true,
+		},
+		{
+			name: "default option fields",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b: []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) {
+				c.Options[0].Required = false
+				c.Options[0].Autocomplete = false
+				c.Options[0].Choices = []*discordgo.ApplicationCommandOptionChoice{}
+				c.Options[0].ChannelTypes = []discordgo.ChannelType{}
+			})},
+			want: true,
+		},
+		{
+			name: "nil and empty options",
+			a:    []*discordgo.ApplicationCommand{{Name: "seigetsu", Description: "Configure the bot"}},
+			b:    []*discordgo.ApplicationCommand{{Name: "seigetsu", Description: "Configure the bot", Options: []*discordgo.ApplicationCommandOption{}}},
+			want: true,
+		},
+		{
+			name: "nil and empty localizations",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b: []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) {
+				c.NameLocalizations = &map[discordgo.Locale]string{}
+				c.DescriptionLocalizations = &map[discordgo.Locale]string{}
+				c.Options[0].NameLocalizations = map[discordgo.Locale]string{}
+			})},
+			want: true,
+		},
+		{
+			name: "same localizations",
+			a: []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) {
+				c.DescriptionLocalizations = &map[discordgo.Locale]string{discordgo.Japanese: "サーバーを管理する"}
+			})},
+			b: []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) {
+				c.DescriptionLocalizations = &map[discordgo.Locale]string{discordgo.Japanese: "サーバーを管理する"}
+			})},
+			want: true,
+		},
+		{
+			name: "DM permission left out",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b:    []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) { c.DMPermission = &yes })},
+			want: true,
+		},
+		{
+			name: "same default member permissions",
+			a:    []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) { c.DefaultMemberPermissions = &admin })},
+			b:    []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) { c.DefaultMemberPermissions = &admin })},
+			want: true,
+		},
+		{
+			name: "description",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b:    []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) { c.Description = "Manage the server" })},
+		},
+		{
+			name: "required option",
+			a:    []*discordgo.ApplicationCommand{command()},
+			b:    []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) { c.Options[0].Required = true })},
+		},
+		{
+			name: "option order",
+			a: []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) {
+				c.Options = append(c.Options, &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionInteger, Name: "count", Description: "The count"})
+			})},
+			b: []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) {
+				c.Options = append([]*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionInteger, Name: "count", Description: "The count"}}, c.Options...)
+			})},
+		},
+		{
+			name: "missing command",
+			a:    []*discordgo.ApplicationCommand{command(), {Name: "seigetsu", Description: "Configure the bot"}},
+			b:    []*discordgo.ApplicationCommand{registered()},
+		},
+		{
+			name: "different localizations",
+			a: []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) {
+				c.DescriptionLocalizations = &map[discordgo.Locale]string{discordgo.Japanese: "サーバーを管理する"}
+			})},
+			b: []*discordgo.ApplicationCommand{registered()},
+		},
+		{
+			name: "option localizations",
+			a: []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) {
+				c.Options[0].NameLocalizations = map[discordgo.Locale]string{discordgo.Japanese: "サーバー"}
+			})},
+			b: []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) {
+				c.Options[0].NameLocalizations = map[discordgo.Locale]string{discordgo.Japanese: "鯖"}
+			})},
+		},
+		{
+			name: "DM permission turned off",
+			a:    []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) { c.DMPermission = &no })},
+			b:    []*discordgo.ApplicationCommand{registered()},
+		},
+		{
+			name: "different default member permissions",
+			a:    []*discordgo.ApplicationCommand{command(func(c *discordgo.ApplicationCommand) { c.DefaultMemberPermissions = &admin })},
+			b:    []*discordgo.ApplicationCommand{registered(func(c *discordgo.ApplicationCommand) { c.DefaultMemberPermissions = &manage })},
+		},
+		{
+			name: "type",
+			a:    []*discordgo.ApplicationCommand{{Name: "pixelmon"}},
+			b:    []*discordgo.ApplicationCommand{{Name: "pixelmon", Type: discordgo.UserApplicationCommand}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commandsEqual(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("commandsEqual() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("commandsEqual() = %v, want %v", got, tt.want)
+			}
+
+			// Comparing is symmetric
+			if got, _ := commandsEqual(tt.b, tt.a); got != tt.want {
+				t.Errorf("commandsEqual() with the sides swapped = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandsEqualRegistered(t *testing.T) {
+	registered := make([]*discordgo.ApplicationCommand, len(Commands))
+	for n, command := range Commands {
+		c := *command
+		c.ID = "1"
+		c.ApplicationID = "2"
+		c.Version = "3"
+		registered[len(Commands)-1-n] = &c
+	}
+
+	if equal, err := commandsEqual(Commands, registered); err != nil || !equal {
+		t.Errorf("commandsEqual() of the registered commands = %v, %v, want true", equal, err)
+	}
+}
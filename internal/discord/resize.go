@@ -0,0 +1,179 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+// maxChoices is the most choices Discord allows for an option
+const maxChoices = 25
+
+// instanceTypeChoices returns the instance types servers can be resized to
+func instanceTypeChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, instanceType := range pixelmon.InstanceTypes {
+		if len(choices) == maxChoices {
+			log.Printf("Only the first %v instance types can be chosen", maxChoices)
+			break
+		}
+
+		name := instanceType.Name
+		if instanceType.Price > 0 {
+			name = fmt.Sprintf("%v (%v/h)", instanceType.Name, formatPrice(instanceType.Price))
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name, Value: instanceType.Name})
+	}
+
+	return choices
+}
+
+// formatPrice formats a cost in USD
+func formatPrice(price float64) string {
+	return fmt.Sprintf("$%.4f", price)
+}
+
+// handleResize changes the instance type of the stopped server and, if asked, schedules reverting it at the next stop
+func handleResize(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server) {
+	var instanceType string
+	var revert bool
+	for _, option := range i.ApplicationCommandData().Options[0].Options {
+		switch option.Name {
+		case "type":
+			instanceType = option.StringValue()
+		case "revert":
+			revert = option.BoolValue()
+		}
+	}
+
+	content := getMessage(i, "resize.resizing", map[string]any{"Type": instanceType})
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	// Wait for a stopping instance and resize it
+	ctx, done := startOperation()
+	defer done()
+	ctx = withProgress(ctx, getLocale(i), content, editResponse(s, i))
+
+	previous, err := pixelmon.Resize(ctx, srv, instanceType)
+	if err != nil {
+		log.Printf("Error: %v", err)
+
+		var text string
+		switch {
+		case errors.Is(err, pixelmon.ErrOnline):
+			text = getMessage(i, "resize.running", nil)
+		case errors.Is(err, pixelmon.ErrInstanceType):
+			text = getMessage(i, "resize.not_allowed", map[string]any{"Type": instanceType})
+		case errors.Is(err, pixelmon.ErrNotSupported):
+			text = getMessage(i, "resize.not_supported", nil)
+		case errors.Is(err, pixelmon.ErrHibernated):
+			text = getMessage(i, "resize.hibernated", nil)
+		default:
+			text = failureMessage(i, "resize.error", err)
+		}
+
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: text,
+		})
+		if err != nil {
+			log.Fatalf("Error sending follow-up message: %v", err)
+		}
+
+		return
+	}
+
+	text := getMessage(i, "resize.success", map[string]any{"Previous": previous, "Type": instanceType})
+	if cost := costMessage(i, previous, instanceType); cost != "" {
+		text += "\n" + cost
+	}
+
+	// Remember what to revert to, keeping the type from before the first resize if it wasn't reverted yet
+	target := pixelmon.DefaultInstanceType
+	if target == "" {
+		if target, _, err = reverts.Get(srv.Key()); err != nil || target == "" {
+			target = previous
+		}
+	}
+	if revert && target != instanceType && srv.Hibernate {
+		// A hibernated instance can't be resized, so it would never be reverted
+		text += "\n" + getMessage(i, "resize.no_revert", nil)
+		if err := reverts.Delete(srv.Key()); err != nil {
+			log.Printf("Error deleting instance type to revert to: %v", err)
+		}
+	} else if revert && target != instanceType {
+		if err := reverts.Put(srv.Key(), target); err != nil {
+			log.Printf("Error saving instance type to revert to: %v", err)
+		} else {
+			text += "\n" + getMessage(i, "resize.revert", map[string]any{"Type": target})
+		}
+	} else if err := reverts.Delete(srv.Key()); err != nil {
+		log.Printf("Error deleting instance type to revert to: %v", err)
+	}
+
+	_, err = s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: text,
+	})
+	if err != nil {
+		log.Fatalf("Error sending follow-up message: %v", err)
+	}
+}
+
+// costMessage describes how much more or less the new instance type costs per hour, or nothing if the prices aren't
+// known
+func costMessage(i *discordgo.InteractionCreate, previous string, instanceType string) string {
+	price, ok := pixelmon.InstancePrice(instanceType)
+	if !ok {
+		return ""
+	}
+
+	previousPrice, ok := pixelmon.InstancePrice(previous)
+	if !ok {
+		return getMessage(i, "resize.price", map[string]any{"Type": instanceType, "Price": formatPrice(price)})
+	}
+
+	difference := price - previousPrice
+	sign := "+"
+	if difference < 0 {
+		sign = "-"
+		difference = -difference
+	}
+
+	return getMessage(i, "resize.cost", map[string]any{"Price": formatPrice(price), "Difference": sign + formatPrice(difference)})
+}
+
+// revertInstanceType changes the instance type of the stopped server back if it was resized until its next stop. It is
+// kept for the next stop if it fails or the instance hibernated.
+func revertInstanceType(ctx context.Context, srv pixelmon.Server) {
+	instanceType, ok, err := reverts.Get(srv.Key())
+	if err != nil {
+		log.Printf("Error loading instance type to revert to: %v", err)
+		return
+	}
+	if !ok || instanceType == "" {
+		return
+	}
+
+	if err := pixelmon.Revert(ctx, srv, instanceType); errors.Is(err, pixelmon.ErrHibernated) {
+		log.Printf("Skipping reverting %v to %v while it is hibernated", srv.Name, instanceType)
+		return
+	} else if err != nil {
+		log.Printf("Error reverting %v to %v: %v", srv.Name, instanceType, err)
+		return
+	}
+
+	if err := reverts.Delete(srv.Key()); err != nil {
+		log.Printf("Error deleting instance type to revert to: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	// requestTimeout limits the calls made while answering an interaction or polling the server
+	requestTimeout = 30 * time.Second
+
+	// shutdownTimeout is how long Shutdown waits for cancelled operations to report back
+	shutdownTimeout = 10 * time.Second
+)
+
+var (
+	// botCtx is cancelled when the bot shuts down
+	botCtx, cancelBot = context.WithCancel(context.Background())
+
+	// operations are the lifecycle operations in progress
+	operations     sync.WaitGroup
+	operationsMu   sync.Mutex
+	shuttingDown   bool
+	shutdownCalled sync.Once
+)
+
+// startOperation returns the context of a lifecycle operation, which is cancelled on shutdown. done must be called
+// when the operation ends so Shutdown can wait for it.
+func startOperation() (ctx context.Context, done func()) {
+	operationsMu.Lock()
+	defer operationsMu.Unlock()
+
+	if shuttingDown {
+		return botCtx, func() {}
+	}
+	operations.Add(1)
+
+	return botCtx, operations.Done
+}
+
+// requestContext returns the context of a quick call, which is cancelled on shutdown or after requestTimeout
+func requestContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(botCtx, requestTimeout)
+}
+
+// Shutdown cancels the operations in progress and waits for them to let their users know. Operations that are rolling
+// back are waited for up to pixelmon.RollbackTimeout.
+func Shutdown() {
+	shutdownCalled.Do(func() {
+		operationsMu.Lock()
+		shuttingDown = true
+		operationsMu.Unlock()
+
+		cancelBot()
+
+		done := make(chan struct{})
+		go func() {
+			operations.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return
+		case <-time.After(shutdownTimeout):
+		}
+
+		// A failed start keeps rolling back when it is cancelled, so give it time to stop the instance again
+		if !pixelmon.RollingBack() {
+			log.Println("Timed out waiting for operations to stop")
+			return
+		}
+		log.Println("Waiting for rollbacks to finish")
+
+		select {
+		case <-done:
+		case <-time.After(pixelmon.RollbackTimeout):
+			log.Println("Timed out waiting for rollbacks to finish")
+		}
+	})
+}
+
+// errorKey returns the message key for an operation that failed with err. It is key unless the bot shut down or the
+// operation timed out.
+func errorKey(key string, err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "error.shutdown"
+	case errors.Is(err, pixelmon.ErrTimeout):
+		return "error.timeout"
+	}
+
+	return key
+}
@@ -0,0 +1,87 @@
+package discord
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const defaultSpawnInterval = "15s"
+
+// StartSpawnAnnouncements tails the logs of the servers and announces legendary and shiny spawns in the notification
+// channels
+func StartSpawnAnnouncements(s *discordgo.Session) {
+	interval, err := time.ParseDuration(getEnv("SPAWN_INTERVAL", defaultSpawnInterval))
+	if err != nil {
+		log.Printf("Error parsing SPAWN_INTERVAL: %v", err)
+		interval, _ = time.ParseDuration(defaultSpawnInterval)
+	}
+
+	parser, err := pixelmon.NewSpawnParser(pixelmon.SpawnPatterns)
+	if err != nil {
+		log.Printf("Error parsing spawn patterns, spawns won't be announced: %v", err)
+		return
+	}
+	if !parser.Enabled() {
+		return
+	}
+
+	go runSpawnAnnouncements(s, interval, parser)
+}
+
+// runSpawnAnnouncements polls the logs of the servers for new lines and announces the spawns in them
+func runSpawnAnnouncements(s *discordgo.Session, interval time.Duration, parser *pixelmon.SpawnParser) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// offsets are where to read each server's log from next, by server key. Servers that were already running when
+	// the bot started are read from the end of their log, so old spawns aren't announced again.
+	offsets := map[string]int64{}
+
+	for {
+		select {
+		case <-botCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, srv := range watchedServers() {
+			offset, ok := offsets[srv.Key()]
+			if !ok {
+				offset = -1
+			}
+
+			lines, next, err := readLog(srv, offset)
+			if errors.Is(err, pixelmon.ErrOffline) {
+				// The service starts a new log when the server starts again
+				offsets[srv.Key()] = 0
+				continue
+			}
+			if err != nil {
+				log.Printf("Error reading the log of %v: %v", srv.Name, err)
+				continue
+			}
+			offsets[srv.Key()] = next
+
+			for _, line := range lines {
+				spawn, ok := parser.Parse(line)
+				if !ok {
+					continue
+				}
+
+				log.Printf("%v spawned on %v", spawn.Pokemon, srv.Name)
+				go notify(s, srv, "spawn."+spawn.Kind, map[string]any{"Pokemon": spawn.Pokemon, "Biome": spawn.Biome})
+			}
+		}
+	}
+}
+
+func readLog(srv pixelmon.Server, offset int64) ([]string, int64, error) {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	return pixelmon.ReadLog(ctx, srv, offset)
+}
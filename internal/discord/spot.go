@@ -0,0 +1,71 @@
+package discord
+
+import (
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const defaultSpotInterval = "30s"
+
+// StartSpotWatch checks the servers for spot interruption notices and saves and backs up the world before their
+// instance is reclaimed. AWS gives two minutes of notice, so the interval should be well under that.
+func StartSpotWatch(s *discordgo.Session) {
+	interval, err := time.ParseDuration(getEnv("SPOT_INTERVAL", defaultSpotInterval))
+	if err != nil {
+		log.Printf("Error parsing SPOT_INTERVAL: %v", err)
+		interval, _ = time.ParseDuration(defaultSpotInterval)
+	}
+
+	go runSpotWatch(s, interval)
+}
+
+// runSpotWatch polls the servers for interruption notices and handles each notice once
+func runSpotWatch(s *discordgo.Session, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// handled are the times of the notices that were handled, by server key
+	handled := map[string]time.Time{}
+
+	for {
+		select {
+		case <-botCtx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, srv := range watchedServers() {
+			notice, err := checkSpotInterruption(srv)
+			if err != nil {
+				log.Printf("Error checking %v for a spot interruption: %v", srv.Name, err)
+				continue
+			}
+			if notice == nil || handled[srv.Key()].Equal(notice.Time) {
+				continue
+			}
+			handled[srv.Key()] = notice.Time
+
+			go notify(s, srv, "spot.interruption", map[string]any{"Action": notice.Action, "Time": notice.Time.Unix()})
+			go handleSpotInterruption(srv, *notice)
+		}
+	}
+}
+
+func checkSpotInterruption(srv pixelmon.Server) (*pixelmon.SpotInterruption, error) {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	return pixelmon.GetSpotInterruption(ctx, srv)
+}
+
+func handleSpotInterruption(srv pixelmon.Server, notice pixelmon.SpotInterruption) {
+	ctx, done := startOperation()
+	defer done()
+
+	if err := pixelmon.HandleSpotInterruption(ctx, srv, notice); err != nil {
+		log.Printf("Error handling %v spot interruption: %v", srv.Name, err)
+	}
+}
@@ -0,0 +1,235 @@
+package discord
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	statusStartID   = "pixelmon_status_start"
+	statusStopID    = "pixelmon_status_stop"
+	statusRefreshID = "pixelmon_status_refresh"
+
+	faviconName = "favicon.png"
+
+	colorOnline  = 0x57F287
+	colorOffline = 0xED4245
+	colorPending = 0xFEE75C
+)
+
+// statusResponseData builds the status embed of the server with its buttons. The favicon is only attached if
+// withFavicon is set.
+func statusResponseData(i *discordgo.InteractionCreate, srv pixelmon.Server, withFavicon bool) *discordgo.InteractionResponseData {
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	info, err := pixelmon.GetServerInfo(ctx, srv)
+	if err != nil {
+		log.Printf("Error: %v", err)
+
+		key := "status.error"
+		switch {
+		case errors.Is(err, pixelmon.ErrNotFound):
+			key = "status.not_found"
+		case errors.Is(err, pixelmon.ErrMultipleInstances):
+			key = "status.multiple"
+		}
+
+		return &discordgo.InteractionResponseData{
+			Content: getMessage(i, key, nil),
+		}
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:     srv.Name,
+		Color:     colorPending,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: getMessage(i, "embed.state", nil), Value: info.State, Inline: true},
+			{Name: getMessage(i, "embed.instance_type", nil), Value: info.InstanceType, Inline: true},
+		},
+	}
+	switch info.State {
+	case "running":
+		embed.Color = colorOnline
+	case "stopped":
+		embed.Color = colorOffline
+	}
+
+	if info.PublicIP != "" {
+		embed.Fields = append(embed.Fields,
+			&discordgo.MessageEmbedField{Name: getMessage(i, "embed.address", nil), Value: fmt.Sprintf("`%v` (`%v`)", info.Address, info.PublicIP), Inline: true},
+			&discordgo.MessageEmbedField{Name: getMessage(i, "embed.uptime", nil), Value: getMessage(i, "embed.uptime_since", map[string]any{"Time": fmt.Sprintf("<t:%d:R>", info.LaunchTime.Unix())}), Inline: true},
+		)
+	}
+
+	data := &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{embed},
+		Components: statusComponents(i, srv),
+	}
+
+	if info.Status == nil || !info.Status.Online {
+		embed.Description = getMessage(i, "status.offline", nil)
+		if info.State == "running" {
+			embed.Color = colorPending
+		}
+
+		return data
+	}
+
+	embed.Description = getMessage(i, "status.online", nil)
+	if info.Status.MOTD.Clean != "" {
+		embed.Description += "\n```\n" + info.Status.MOTD.Clean + "\n```"
+	}
+
+	players := strconv.Itoa(info.Status.Players.Online) + "/" + strconv.Itoa(info.Status.Players.Max)
+	if len(info.Status.Players.List) > 0 {
+		names := make([]string, 0, len(info.Status.Players.List))
+		for _, player := range info.Status.Players.List {
+			names = append(names, player.NameClean)
+		}
+		players += "\n" + formatPlayers(names)
+	}
+
+	embed.Fields = append(embed.Fields,
+		&discordgo.MessageEmbedField{Name: getMessage(i, "embed.version", nil), Value: info.Status.Version.NameClean, Inline: true},
+		&discordgo.MessageEmbedField{Name: getMessage(i, "embed.latency", nil), Value: info.Latency.Round(time.Millisecond).String(), Inline: true},
+		&discordgo.MessageEmbedField{Name: getMessage(i, "embed.players", nil), Value: players},
+	)
+
+	if withFavicon {
+		favicon, err := info.Status.GetFavicon()
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return data
+		}
+
+		data.Files = []*discordgo.File{
+			{
+				Name:        faviconName,
+				ContentType: "image/png",
+				Reader:      bytes.NewReader(favicon),
+			},
+		}
+	}
+	embed.Thumbnail = &discordgo.MessageEmbedThumbnail{URL: "attachment://" + faviconName}
+
+	return data
+}
+
+// statusComponents returns the Start/Stop/Refresh buttons for the status embed of the server
+func statusComponents(i *discordgo.InteractionCreate, srv pixelmon.Server) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    getMessage(i, "button.start", nil),
+					Style:    discordgo.SuccessButton,
+					CustomID: statusStartID + ":" + srv.Name,
+				},
+				discordgo.Button{
+					Label:    getMessage(i, "button.stop", nil),
+					Style:    discordgo.DangerButton,
+					CustomID: statusStopID + ":" + srv.Name,
+				},
+				discordgo.Button{
+					Label:    getMessage(i, "button.refresh", nil),
+					Style:    discordgo.SecondaryButton,
+					CustomID: statusRefreshID + ":" + srv.Name,
+				},
+			},
+		},
+	}
+}
+
+// statusStart handles the Start button on the status embed
+func statusStart(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !checkPermission(s, i, "start") {
+		return
+	}
+
+	srv, ok := checkServer(s, i)
+	if !ok || !checkCooldown(s, i, srv, "start") {
+		return
+	}
+
+	handleStart(s, i, srv)
+}
+
+// statusStop handles the Stop button on the status embed
+func statusStop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !checkPermission(s, i, "stop") {
+		return
+	}
+
+	srv, ok := checkServer(s, i)
+	if !ok || !checkCooldown(s, i, srv, "stop") {
+		return
+	}
+
+	handleStop(s, i, srv)
+}
+
+// statusRefresh handles the Refresh button on the status embed
+func statusRefresh(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !checkPermission(s, i, "status") {
+		return
+	}
+
+	srv, ok := checkServer(s, i)
+	if !ok {
+		return
+	}
+
+	respondStatus(s, i, srv, true)
+}
+
+// respondStatus responds with the status embed of the server. Getting the status can take longer than Discord waits
+// for a response, so the response is deferred and edited once the status is known. If update is set, the message of
+// the component is updated instead of sending a new one.
+func respondStatus(s *discordgo.Session, i *discordgo.InteractionCreate, srv pixelmon.Server, update bool) {
+	responseType := discordgo.InteractionResponseDeferredChannelMessageWithSource
+	withFavicon := true
+	if update {
+		responseType = discordgo.InteractionResponseDeferredMessageUpdate
+		withFavicon = len(i.Message.Attachments) == 0
+	}
+
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: responseType})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+
+	data := statusResponseData(i, srv, withFavicon)
+	if update {
+		// The whole message is replaced, so an error doesn't leave the previous status and its buttons behind
+		if data.Embeds == nil {
+			data.Embeds = []*discordgo.MessageEmbed{}
+		}
+		if data.Components == nil {
+			data.Components = []discordgo.MessageComponent{}
+		}
+	}
+
+	edit := &discordgo.WebhookEdit{Files: data.Files}
+	if data.Content != "" || update {
+		edit.Content = &data.Content
+	}
+	if data.Embeds != nil {
+		edit.Embeds = &data.Embeds
+	}
+	if data.Components != nil {
+		edit.Components = &data.Components
+	}
+
+	if _, err := s.InteractionResponseEdit(i.Interaction, edit); err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package discord
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+func TestRespondStatusError(t *testing.T) {
+	tests := []struct {
+		name   string
+		update bool
+
+		// cleared is whether the edit removes the embeds and buttons of the message
+		cleared bool
+	}{
+		{name: "new message"},
+		{name: "refresh", update: true, cleared: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransport{}
+			s := newTestSession(t, fake)
+
+			// The container name is invalid, so getting the status fails
+			srv := pixelmon.Server{Name: "Kanto", Backend: pixelmon.BackendDocker, InstanceID: "not a container"}
+			i := newTestCommand("status")
+			if tt.update {
+				i.Type = discordgo.InteractionMessageComponent
+				i.Data = discordgo.MessageComponentInteractionData{CustomID: statusRefreshID + ":" + srv.Name}
+				i.Message = &discordgo.Message{ID: "50"}
+			}
+
+			respondStatus(s, i, srv, tt.update)
+
+			if len(fake.requests) != 2 {
+				t.Fatalf("sent %v requests, want the deferred response and its edit: %+v", len(fake.requests), fake.requests)
+			}
+
+			edit := fake.requests[1]
+			if edit.Method != http.MethodPatch || edit.Path != "/api/v9/webhooks/20/token/messages/@original" {
+				t.Fatalf("edited with %+v, want an edit of the deferred response", edit)
+			}
+			if edit.Body["content"] != getMessage(i, "status.error", nil) {
+				t.Errorf("content = %v, want the error", edit.Body["content"])
+			}
+
+			embeds, hasEmbeds := edit.Body["embeds"].([]any)
+			components, hasComponents := edit.Body["components"].([]any)
+			if tt.cleared && (!hasEmbeds || len(embeds) != 0 || !hasComponents || len(components) != 0) {
+				t.Errorf("edited with %+v, want the embeds and components cleared", edit.Body)
+			}
+			if !tt.cleared && (hasEmbeds || hasComponents) {
+				t.Errorf("edited with %+v, want only the error", edit.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package discord
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+)
+
+const (
+	confirmStopID = "pixelmon_stop_confirm"
+	cancelStopID  = "pixelmon_stop_cancel"
+	vetoStopID    = "pixelmon_stop_veto"
+)
+
+// stopWarnings are the in-game warnings broadcast before stopping, in order
+var stopWarnings = []time.Duration{
+	5 * time.Minute,
+	1 * time.Minute,
+	10 * time.Second,
+}
+
+var (
+	// scheduledStops are keyed by server and closed when the scheduled stop is vetoed
+	scheduledStops   = make(map[string]chan struct{})
+	scheduledStopsMu sync.Mutex
+)
+
+// stopServer stops the server's Pixelmon service and then its instance
+func stopServer(ctx context.Context, srv pixelmon.Server) error {
+	// Stop Pixelmon service
+	if err := pixelmon.StopPixelmon(ctx, srv); err != nil {
+		return err
+	}
+
+	// Stop Pixelmon instance. Containers and processes exit with the service, so they are already stopped.
+	if err := pixelmon.Stop(ctx, srv); err != nil && !errors.Is(err, pixelmon.ErrOffline) {
+		return err
+	}
+
+	// Revert an instance that was resized until this stop
+	revertInstanceType(ctx, srv)
+
+	return nil
+}
+
+// isStopScheduled checks if a stop countdown of the server is in progress
+func isStopScheduled(srv pixelmon.Server) bool {
+	scheduledStopsMu.Lock()
+	defer scheduledStopsMu.Unlock()
+
+	return scheduledStops[srv.Key()] != nil
+}
+
+// confirmStopComponents returns the Confirm/Cancel buttons for stopping the server
+func confirmStopComponents(i *discordgo.InteractionCreate, srv pixelmon.Server) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    getMessage(i, "button.confirm", nil),
+					Style:    discordgo.DangerButton,
+					CustomID: confirmStopID + ":" + srv.Name,
+				},
+				discordgo.Button{
+					Label:    getMessage(i, "button.cancel", nil),
+					Style:    discordgo.SecondaryButton,
+					CustomID: cancelStopID + ":" + srv.Name,
+				},
+			},
+		},
+	}
+}
+
+// vetoStopComponents returns the Veto button for a scheduled stop of the server
+func vetoStopComponents(locale string, srv pixelmon.Server) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    getMessageIn(locale, "button.veto", nil),
+					Style:    discordgo.PrimaryButton,
+					CustomID: vetoStopID + ":" + srv.Name,
+				},
+			},
+		},
+	}
+}
+
+// formatPlayers formats a list of player names for a message
+func formatPlayers(players []string) string {
+	return "`" + strings.Join(players, "`, `") + "`"
+}
+
+// confirmStop schedules a stop after the user confirms the prompt
+func confirmStop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isAllowed(s, i, "stop") {
+		updateComponentMessage(s, i, getMessage(i, "error.not_allowed", nil))
+		return
+	}
+
+	srv, ok := getServer(i)
+	if !ok {
+		updateComponentMessage(s, i, getMessage(i, "config.unknown_server", map[string]any{"Name": srv.Name}))
+		return
+	}
+
+	scheduledStopsMu.Lock()
+	if scheduledStops[srv.Key()] != nil {
+		scheduledStopsMu.Unlock()
+		updateComponentMessage(s, i, getMessage(i, "stop.already_scheduled", nil))
+		return
+	}
+	veto := make(chan struct{})
+	scheduledStops[srv.Key()] = veto
+	scheduledStopsMu.Unlock()
+
+	locale := getLocale(i)
+	updateComponentMessage(s, i, getMessage(i, "stop.scheduled", map[string]any{"Time": messages.Duration(locale, stopWarnings[0])}))
+
+	msg, err := s.ChannelMessageSendComplex(i.ChannelID, &discordgo.MessageSend{
+		Content:    countdownMessage(locale, srv, getUserID(i), stopWarnings[0]),
+		Components: vetoStopComponents(locale, srv),
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+
+		scheduledStopsMu.Lock()
+		delete(scheduledStops, srv.Key())
+		scheduledStopsMu.Unlock()
+
+		return
+	}
+
+	go runStopCountdown(s, msg, locale, srv, getUserID(i), veto)
+}
+
+// cancelStop dismisses the confirmation prompt
+func cancelStop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if !isAllowed(s, i, "stop") {
+		updateComponentMessage(s, i, getMessage(i, "error.not_allowed", nil))
+		return
+	}
+
+	updateComponentMessage(s, i, getMessage(i, "stop.cancelled", nil))
+}
+
+// vetoStop cancels the scheduled stop. Any user can veto a stop.
+func vetoStop(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	srv, _ := getServer(i)
+
+	scheduledStopsMu.Lock()
+	veto := scheduledStops[srv.Key()]
+	delete(scheduledStops, srv.Key())
+	scheduledStopsMu.Unlock()
+
+	if veto == nil {
+		updateComponentMessage(s, i, getMessage(i, "stop.not_scheduled", nil))
+		return
+	}
+	close(veto)
+
+	updateComponentMessage(s, i, getMessage(i, "stop.vetoed", nil))
+
+	ctx, cancel := requestContext()
+	defer cancel()
+
+	if err := pixelmon.SendMessage(ctx, srv, getMessageIn(catalog.DefaultLocale, "ingame.stop_cancelled", nil)); err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
+
+// runStopCountdown warns players in-game before stopping the server unless the stop is vetoed
+func runStopCountdown(s *discordgo.Session, msg *discordgo.Message, locale string, srv pixelmon.Server, userID string, veto chan struct{}) {
+	ctx, done := startOperation()
+	defer done()
+
+	for n, remaining := range stopWarnings {
+		text := getMessageIn(catalog.DefaultLocale, "ingame.stopping", map[string]any{"Time": messages.Duration(catalog.DefaultLocale, remaining)})
+		if err := pixelmon.SendMessage(ctx, srv, text); err != nil {
+			log.Printf("Error: %v", err)
+		}
+
+		if n > 0 {
+			content := countdownMessage(locale, srv, userID, remaining)
+			components := vetoStopComponents(locale, srv)
+			if _, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+				ID:         msg.ID,
+				Channel:    msg.ChannelID,
+				Content:    &content,
+				Components: components,
+			}); err != nil {
+				log.Printf("Error: %v", err)
+			}
+		}
+
+		wait := remaining
+		if n+1 < len(stopWarnings) {
+			wait -= stopWarnings[n+1]
+		}
+
+		select {
+		case <-veto:
+			return
+		case <-ctx.Done():
+			// The bot is shutting down, so nobody would be left to stop the server
+			scheduledStopsMu.Lock()
+			delete(scheduledStops, srv.Key())
+			scheduledStopsMu.Unlock()
+
+			editStopMessage(s, msg, getMessageIn(locale, "error.shutdown", map[string]any{"Server": srv.Name}))
+			return
+		case <-time.After(wait):
+		}
+	}
+
+	// Past the point of no return
+	scheduledStopsMu.Lock()
+	select {
+	case <-veto:
+		scheduledStopsMu.Unlock()
+		return
+	default:
+		delete(scheduledStops, srv.Key())
+	}
+	scheduledStopsMu.Unlock()
+
+	content := getMessageIn(locale, "stop.stopping", map[string]any{"Server": srv.Name})
+	editStopMessage(s, msg, content)
+
+	ctx = withProgress(ctx, locale, content, func(content string) {
+		editStopMessage(s, msg, content)
+	})
+	if err := stopServer(ctx, srv); err != nil {
+		log.Printf("Error: %v", err)
+		editStopMessage(s, msg, failureMessageIn(locale, "stop.error", err, map[string]any{"Server": srv.Name}))
+		return
+	}
+
+	editStopMessage(s, msg, getMessageIn(locale, "status.offline", map[string]any{"Server": srv.Name}))
+}
+
+// countdownMessage returns the content of the countdown message
+func countdownMessage(locale string, srv pixelmon.Server, userID string, remaining time.Duration) string {
+	return getMessageIn(locale, "stop.countdown", map[string]any{"Time": messages.Duration(locale, remaining), "User": "<@" + userID + ">", "Server": srv.Name})
+}
+
+// editStopMessage replaces the countdown message and removes the veto button
+func editStopMessage(s *discordgo.Session, msg *discordgo.Message, content string) {
+	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		ID:         msg.ID,
+		Channel:    msg.ChannelID,
+		Content:    &content,
+		Components: []discordgo.MessageComponent{},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
+	}
+}
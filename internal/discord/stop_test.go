@@ -0,0 +1,155 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
+	"github.com/kn-lim/seigetsu-bot/internal/store"
+)
+
+// fakeRequest is a request the bot sent to Discord
+type fakeRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// fakeTransport stands in for Discord and mcstatus.io, recording the requests to Discord
+type fakeTransport struct {
+	mu       sync.Mutex
+	requests []fakeRequest
+
+	// mcstatus answers requests to mcstatus.io
+	mcstatus func(host string) (int, string)
+}
+
+func (f *fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	status, body := http.StatusOK, `{"id": "2"}`
+	if r.URL.Host == "api.mcstatus.io" {
+		status, body = f.mcstatus(strings.TrimPrefix(r.URL.Path, "/v2/status/java/"))
+	} else {
+		request := fakeRequest{Method: r.Method, Path: r.URL.Path}
+		if r.Body != nil {
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &request.Body)
+		}
+
+		f.mu.Lock()
+		f.requests = append(f.requests, request)
+		f.mu.Unlock()
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Request:    r,
+	}, nil
+}
+
+// newTestSession returns a session that sends its requests and those to mcstatus.io to the fake, with the bot's data
+// in a temporary store
+func newTestSession(t *testing.T, fake *fakeTransport) *discordgo.Session {
+	t.Helper()
+
+	s, err := store.Open(filepath.Join(t.TempDir(), "seigetsu.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	SetStore(s)
+
+	transport := http.DefaultClient.Transport
+	http.DefaultClient.Transport = fake
+	t.Cleanup(func() { http.DefaultClient.Transport = transport })
+
+	session, err := discordgo.New("Bot token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	session.Client = &http.Client{Transport: fake}
+
+	return session
+}
+
+// newTestCommand returns the interaction of a slash command by a user in a guild
+func newTestCommand(name string, options ...*discordgo.ApplicationCommandInteractionDataOption) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:      "10",
+		AppID:   "20",
+		Token:   "token",
+		Type:    discordgo.InteractionApplicationCommand,
+		GuildID: "30",
+		Member:  &discordgo.Member{User: &discordgo.User{ID: "40"}},
+		Locale:  discordgo.EnglishUS,
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name:    "pixelmon",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{{Name: name, Type: discordgo.ApplicationCommandOptionSubCommand, Options: options}},
+		},
+	}}
+}
+
+func TestHandleStopConfirmationIsEphemeral(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantText string
+	}{
+		{
+			name:     "players online",
+			status:   http.StatusOK,
+			body:     `{"online": true, "players": {"online": 1, "list": [{"name_clean": "Ash"}]}}`,
+			wantText: "`Ash`",
+		},
+		{
+			name:     "players unknown",
+			status:   http.StatusBadRequest,
+			body:     `{}`,
+			wantText: "Couldn't check if players are online",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransport{mcstatus: func(host string) (int, string) {
+				if host != "kanto.example.com" {
+					t.Errorf("looked up the players of %v, want kanto.example.com", host)
+				}
+				return tt.status, tt.body
+			}}
+			s := newTestSession(t, fake)
+			srv := pixelmon.Server{Name: "Kanto", Domain: "example.com", Subdomain: "kanto"}
+
+			handleStop(s, newTestCommand("stop"), srv)
+
+			if len(fake.requests) != 2 {
+				t.Fatalf("sent %v requests, want the deferred response and its edit: %+v", len(fake.requests), fake.requests)
+			}
+
+			deferred := fake.requests[0]
+			data, _ := deferred.Body["data"].(map[string]any)
+			if deferred.Path != "/api/v9/interactions/10/token/callback" ||
+				deferred.Body["type"] != float64(discordgo.InteractionResponseDeferredChannelMessageWithSource) ||
+				data["flags"] != float64(discordgo.MessageFlagsEphemeral) {
+				t.Errorf("deferred the response with %+v, want an ephemeral deferred response", deferred)
+			}
+
+			prompt := fake.requests[1]
+			content, _ := prompt.Body["content"].(string)
+			components, _ := prompt.Body["components"].([]any)
+			if prompt.Method != http.MethodPatch || prompt.Path != "/api/v9/webhooks/20/token/messages/@original" ||
+				!strings.Contains(content, tt.wantText) || len(components) != 1 {
+				t.Errorf("prompted with %+v, want the confirmation in the deferred response", prompt)
+			}
+		})
+	}
+}
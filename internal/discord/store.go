@@ -0,0 +1,32 @@
+package discord
+
+import (
+	"github.com/kn-lim/seigetsu-bot/internal/permissions"
+	"github.com/kn-lim/seigetsu-bot/internal/store"
+)
+
+var (
+	// guildConfigs are keyed by guild ID
+	guildConfigs store.Collection[guildConfig]
+
+	// policies are keyed by guild ID
+	policies store.Collection[permissions.Policy]
+
+	// storedDashboards are keyed by guild ID
+	storedDashboards store.Collection[*dashboard]
+
+	// accounts are the Minecraft usernames that Discord users whitelisted, keyed by user ID
+	accounts store.Collection[[]string]
+
+	// reverts are the instance types to revert resized servers to at their next stop, keyed by server key
+	reverts store.Collection[string]
+)
+
+// SetStore sets where the bot keeps its data. It must be called before the session is opened.
+func SetStore(s *store.Store) {
+	guildConfigs = store.NewCollection[guildConfig](s, store.Guilds)
+	policies = store.NewCollection[permissions.Policy](s, store.Permissions)
+	storedDashboards = store.NewCollection[*dashboard](s, store.Dashboards)
+	accounts = store.NewCollection[[]string](s, store.Accounts)
+	reverts = store.NewCollection[string](s, store.Reverts)
+}
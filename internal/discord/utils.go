@@ -1,43 +1,152 @@
 package discord
 
 import (
+	"log"
+	"os"
+
 	"github.com/bwmarrin/discordgo"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
 	"github.com/kn-lim/seigetsu-bot/internal/pixelmon"
 )
 
-// getRequiredRoleIDs gets the role IDs of roles required for users to run certain commands
-func getRequiredRoleIDs(s *discordgo.Session, i *discordgo.InteractionCreate) (map[string]string, error) {
-	if len(pixelmon.RequiredRoleNames) == 0 {
-		return nil, nil
+// messages is the catalog of every message the bot sends
+var messages = catalog.Default()
+
+// isAdmin checks to see if the user has the Administrator permission in the guild
+func isAdmin(i *discordgo.InteractionCreate) bool {
+	if i.Member == nil {
+		return false
+	}
+
+	return i.Member.Permissions&discordgo.PermissionAdministrator != 0
+}
+
+// getUserID gets the ID of the user who created the interaction
+func getUserID(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.ID
+	}
+	if i.User != nil {
+		return i.User.ID
+	}
+
+	return ""
+}
+
+// subcommandOptions gets the options of the interaction's subcommand, which may be in a subcommand group
+func subcommandOptions(i *discordgo.InteractionCreate) []*discordgo.ApplicationCommandInteractionDataOption {
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		return nil
 	}
 
-	// Fetch all roles of the guild
-	roles, err := s.GuildRoles(i.GuildID)
+	subcommand := options[0]
+	if subcommand.Type == discordgo.ApplicationCommandOptionSubCommandGroup && len(subcommand.Options) > 0 {
+		subcommand = subcommand.Options[0]
+	}
+
+	return subcommand.Options
+}
+
+// updateComponentMessage replaces the message a component is attached to and removes its components
+func updateComponentMessage(s *discordgo.Session, i *discordgo.InteractionCreate, content string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	})
 	if err != nil {
-		return nil, err
+		log.Printf("Error: %v", err)
 	}
+}
 
-	requiredRoles := make(map[string]string)
-	for _, role := range roles {
-		for _, name := range pixelmon.RequiredRoleNames {
-			if role.Name == name {
-				requiredRoles[name] = role.ID
-			}
-		}
+// getEnv returns the environment variable or the fallback if it isn't set
+func getEnv(key string, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return fallback
+}
+
+// getLocale gets the language of the interaction, preferring the guild's configured language, then the guild's
+// locale and then the user's
+func getLocale(i *discordgo.InteractionCreate) string {
+	if language := getGuildConfig(i.GuildID).Language; language != "" {
+		return language
+	}
+	if i.GuildLocale != nil && messages.Has(string(*i.GuildLocale)) {
+		return string(*i.GuildLocale)
 	}
 
-	return requiredRoles, nil
+	return string(i.Locale)
 }
 
-// checkForMinecraftersRole checks to see if the user has the Minecrafters role
-func checkForMinecraftersRole(requiredRoles map[string]string, s *discordgo.Session, i *discordgo.InteractionCreate) bool {
-	hasRole := false
-	for _, roleID := range i.Member.Roles {
-		if roleID == requiredRoles[pixelmon.MinecraftersRoleName] {
-			hasRole = true
-			break
+// getMessage renders a message from the catalog in the language of the interaction
+func getMessage(i *discordgo.InteractionCreate, key string, data map[string]any) string {
+	if data == nil {
+		data = make(map[string]any)
+	}
+	if _, ok := data["User"]; !ok {
+		data["User"] = "<@" + getUserID(i) + ">"
+	}
+	if _, ok := data["Server"]; !ok {
+		srv, _ := getServer(i)
+		data["Server"] = srv.Name
+	}
+
+	return getMessageIn(getLocale(i), key, data)
+}
+
+// getMessageIn renders a message from the catalog in the locale
+func getMessageIn(locale string, key string, data map[string]any) string {
+	if data == nil {
+		data = make(map[string]any)
+	}
+	if _, ok := data["Server"]; !ok {
+		data["Server"] = pixelmon.ServerName
+	}
+
+	return messages.Get(locale, key, data)
+}
+
+// localizeCommand sets the localized names and descriptions of the command and its options from the catalog.
+// Keys are "command.<name>[.<option>...].name" and "command.<name>[.<option>...].description".
+func localizeCommand(command *discordgo.ApplicationCommand) {
+	key := "command." + command.Name
+	if names := getLocalizations(key + ".name"); len(names) > 0 {
+		command.NameLocalizations = &names
+	}
+	if descriptions := getLocalizations(key + ".description"); len(descriptions) > 0 {
+		command.DescriptionLocalizations = &descriptions
+	}
+
+	localizeOptions(key, command.Options)
+}
+
+// localizeOptions sets the localized names and descriptions of the options and their sub-options
+func localizeOptions(prefix string, options []*discordgo.ApplicationCommandOption) {
+	for _, option := range options {
+		key := prefix + "." + option.Name
+		if names := getLocalizations(key + ".name"); len(names) > 0 {
+			option.NameLocalizations = names
 		}
+		if descriptions := getLocalizations(key + ".description"); len(descriptions) > 0 {
+			option.DescriptionLocalizations = descriptions
+		}
+
+		localizeOptions(key, option.Options)
+	}
+}
+
+// getLocalizations gets the message in every locale other than the default
+func getLocalizations(key string) map[discordgo.Locale]string {
+	localizations := make(map[discordgo.Locale]string)
+	for locale, text := range messages.Localizations(key, map[string]any{"Server": pixelmon.ServerName}) {
+		localizations[discordgo.Locale(locale)] = text
 	}
 
-	return hasRole
+	return localizations
 }
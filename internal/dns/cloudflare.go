@@ -0,0 +1,224 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const defaultCloudflareURL = "https://api.cloudflare.com/client/v4"
+
+// Cloudflare manages the records of a Cloudflare zone. The zone is the zone ID.
+type Cloudflare struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// cloudflareRecord is a DNS record in the Cloudflare API
+type cloudflareRecord struct {
+	ID      string             `json:"id,omitempty"`
+	Name    string             `json:"name"`
+	Type    string             `json:"type"`
+	Content string             `json:"content,omitempty"`
+	TTL     int64              `json:"ttl"`
+	Proxied *bool              `json:"proxied,omitempty"`
+	Data    *cloudflareSRVData `json:"data,omitempty"`
+}
+
+// cloudflareSRVData is the value of an SRV record in the Cloudflare API
+type cloudflareSRVData struct {
+	Priority int    `json:"priority"`
+	Weight   int    `json:"weight"`
+	Port     int    `json:"port"`
+	Target   string `json:"target"`
+}
+
+// cloudflareMessage is an error in a Cloudflare API response
+type cloudflareMessage struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// CloudflareError is an unsuccessful response from the Cloudflare API
+type CloudflareError struct {
+	Status   int
+	Messages []cloudflareMessage
+}
+
+func (e *CloudflareError) Error() string {
+	var messages []string
+	for _, m := range e.Messages {
+		messages = append(messages, fmt.Sprintf("%v (%v)", m.Message, m.Code))
+	}
+
+	return fmt.Sprintf("cloudflare responded with %v: %v", e.Status, strings.Join(messages, ", "))
+}
+
+// Retryable reports whether the request might succeed later, which is when Cloudflare is rate limiting or failing
+func (e *CloudflareError) Retryable() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= http.StatusInternalServerError
+}
+
+// NewCloudflare returns a Cloudflare provider using the API token in CLOUDFLARE_API_TOKEN. CLOUDFLARE_API_URL replaces
+// the Cloudflare API URL, like for a local stand-in.
+func NewCloudflare() *Cloudflare {
+	baseURL, ok := os.LookupEnv("CLOUDFLARE_API_URL")
+	if !ok {
+		baseURL = defaultCloudflareURL
+	}
+
+	return &Cloudflare{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   os.Getenv("CLOUDFLARE_API_TOKEN"),
+		client:  http.DefaultClient,
+	}
+}
+
+// Upsert implements Provider. Cloudflare applies changes at once, so there is no change ID.
+func (p *Cloudflare) Upsert(ctx context.Context, zone string, records []Record) (string, error) {
+	for _, record := range records {
+		existing, err := p.list(ctx, zone, record)
+		if err != nil {
+			return "", err
+		}
+
+		body, err := cloudflareBody(record)
+		if err != nil {
+			return "", err
+		}
+
+		// Replace the first record and delete the rest, so the name and type only have this record
+		if len(existing) == 0 {
+			err = p.do(ctx, http.MethodPost, "/zones/"+url.PathEscape(zone)+"/dns_records", body, nil)
+		} else {
+			err = p.do(ctx, http.MethodPut, "/zones/"+url.PathEscape(zone)+"/dns_records/"+url.PathEscape(existing[0].ID), body, nil)
+			for _, extra := range existing[1:] {
+				if err != nil {
+					break
+				}
+				err = p.do(ctx, http.MethodDelete, "/zones/"+url.PathEscape(zone)+"/dns_records/"+url.PathEscape(extra.ID), nil, nil)
+			}
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// Delete implements Provider
+func (p *Cloudflare) Delete(ctx context.Context, zone string, records []Record) error {
+	for _, record := range records {
+		existing, err := p.list(ctx, zone, record)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range existing {
+			if err := p.do(ctx, http.MethodDelete, "/zones/"+url.PathEscape(zone)+"/dns_records/"+url.PathEscape(r.ID), nil, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Synced implements Provider. Cloudflare changes are live once they are made.
+func (p *Cloudflare) Synced(ctx context.Context, changeID string) (bool, error) {
+	return true, nil
+}
+
+// list returns the records with the name and type of the record
+func (p *Cloudflare) list(ctx context.Context, zone string, record Record) ([]cloudflareRecord, error) {
+	query := url.Values{"name": {record.Name}, "type": {record.Type}}
+
+	var records []cloudflareRecord
+	err := p.do(ctx, http.MethodGet, "/zones/"+url.PathEscape(zone)+"/dns_records?"+query.Encode(), nil, &records)
+
+	return records, err
+}
+
+// do sends a request to the Cloudflare API and decodes the result of the response into result, if set
+func (p *Cloudflare) do(ctx context.Context, method string, path string, body any, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Success bool                `json:"success"`
+		Errors  []cloudflareMessage `json:"errors"`
+		Result  json.RawMessage     `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("invalid cloudflare response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || !response.Success {
+		return &CloudflareError{Status: resp.StatusCode, Messages: response.Errors}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(response.Result, result)
+}
+
+// cloudflareBody returns the record as the Cloudflare API takes it
+func cloudflareBody(record Record) (cloudflareRecord, error) {
+	body := cloudflareRecord{Name: record.Name, Type: record.Type, TTL: record.TTL}
+	if record.Type != TypeSRV {
+		// Minecraft doesn't go through Cloudflare's proxy
+		proxied := false
+		body.Content = record.Value
+		body.Proxied = &proxied
+		return body, nil
+	}
+
+	fields := strings.Fields(record.Value)
+	if len(fields) != 4 {
+		return body, fmt.Errorf("invalid SRV value %q", record.Value)
+	}
+	var numbers [3]int
+	for n := range numbers {
+		var err error
+		if numbers[n], err = strconv.Atoi(fields[n]); err != nil {
+			return body, fmt.Errorf("invalid SRV value %q", record.Value)
+		}
+	}
+	body.Data = &cloudflareSRVData{
+		Priority: numbers[0],
+		Weight:   numbers[1],
+		Port:     numbers[2],
+		Target:   strings.TrimSuffix(fields[3], "."),
+	}
+
+	return body, nil
+}
@@ -0,0 +1,190 @@
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeCloudflare is a stand-in for the DNS records API of a Cloudflare zone
+type fakeCloudflare struct {
+	mu      sync.Mutex
+	zone    string
+	records map[string]cloudflareRecord
+	nextID  int
+}
+
+func (f *fakeCloudflare) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer token" {
+		writeCloudflare(w, http.StatusForbidden, nil, "Invalid API token")
+		return
+	}
+
+	prefix := "/zones/" + f.zone + "/dns_records"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeCloudflare(w, http.StatusNotFound, nil, "Unknown zone")
+		return
+	}
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+
+	var body cloudflareRecord
+	if r.Body != nil && (r.Method == http.MethodPost || r.Method == http.MethodPut) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeCloudflare(w, http.StatusBadRequest, nil, err.Error())
+			return
+		}
+	}
+
+	switch {
+	case r.Method == http.MethodGet && id == "":
+		var list []cloudflareRecord
+		for _, record := range f.records {
+			if record.Name == r.URL.Query().Get("name") && record.Type == r.URL.Query().Get("type") {
+				list = append(list, record)
+			}
+		}
+		sort.Slice(list, func(a, b int) bool { return list[a].ID < list[b].ID })
+		writeCloudflare(w, http.StatusOK, list, "")
+	case r.Method == http.MethodPost && id == "":
+		f.nextID++
+		body.ID = strconv.Itoa(f.nextID)
+		f.records[body.ID] = body
+		writeCloudflare(w, http.StatusOK, body, "")
+	case r.Method == http.MethodPut && id != "":
+		body.ID = id
+		f.records[id] = body
+		writeCloudflare(w, http.StatusOK, body, "")
+	case r.Method == http.MethodDelete && id != "":
+		delete(f.records, id)
+		writeCloudflare(w, http.StatusOK, map[string]string{"id": id}, "")
+	default:
+		writeCloudflare(w, http.StatusMethodNotAllowed, nil, "Method not allowed")
+	}
+}
+
+// writeCloudflare writes a response of the Cloudflare API
+func writeCloudflare(w http.ResponseWriter, status int, result any, message string) {
+	response := map[string]any{"success": message == "", "result": result, "errors": []cloudflareMessage{}}
+	if message != "" {
+		response["errors"] = []cloudflareMessage{{Code: status, Message: message}}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// newTestCloudflare returns a provider that uses the fake API
+func newTestCloudflare(t *testing.T, handler http.Handler) *Cloudflare {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("CLOUDFLARE_API_URL", server.URL+"/")
+	t.Setenv("CLOUDFLARE_API_TOKEN", "token")
+
+	return NewCloudflare()
+}
+
+func TestCloudflare(t *testing.T) {
+	fake := &fakeCloudflare{
+		zone: "zone1",
+		records: map[string]cloudflareRecord{
+			// A leftover duplicate, which upserting replaces
+			"old1": {ID: "old1", Name: "mc.example.com", Type: TypeA, Content: "198.51.100.1", TTL: 60},
+			"old2": {ID: "old2", Name: "mc.example.com", Type: TypeA, Content: "198.51.100.2", TTL: 60},
+			"www":  {ID: "www", Name: "www.example.com", Type: TypeA, Content: "198.51.100.3", TTL: 60},
+		},
+	}
+	p := newTestCloudflare(t, fake)
+	ctx := context.Background()
+
+	records := []Record{
+		{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: 300},
+		{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25566 mc.example.com", TTL: 300},
+	}
+	changeID, err := p.Upsert(ctx, "zone1", records)
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if synced, err := p.Synced(ctx, changeID); !synced || err != nil {
+		t.Errorf("Synced() = %v, %v, want true", synced, err)
+	}
+
+	proxied := false
+	want := map[string]cloudflareRecord{
+		"old1": {ID: "old1", Name: "mc.example.com", Type: TypeA, Content: "203.0.113.10", TTL: 300, Proxied: &proxied},
+		"www":  {ID: "www", Name: "www.example.com", Type: TypeA, Content: "198.51.100.3", TTL: 60},
+		"1": {ID: "1", Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, TTL: 300, Data: &cloudflareSRVData{
+			Priority: 0, Weight: 5, Port: 25566, Target: "mc.example.com",
+		}},
+	}
+	assertCloudflareRecords(t, fake, want)
+
+	if err := p.Delete(ctx, "zone1", []Record{{Name: "mc.example.com", Type: TypeA}, {Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV}}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	assertCloudflareRecords(t, fake, map[string]cloudflareRecord{"www": want["www"]})
+}
+
+func TestCloudflareErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		retryable bool
+	}{
+		{name: "forbidden", status: http.StatusForbidden, retryable: false},
+		{name: "rate limited", status: http.StatusTooManyRequests, retryable: true},
+		{name: "server error", status: http.StatusBadGateway, retryable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestCloudflare(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeCloudflare(w, tt.status, nil, http.StatusText(tt.status))
+			}))
+
+			_, err := p.Upsert(context.Background(), "zone1", []Record{{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: 300}})
+			var cfErr *CloudflareError
+			if !errors.As(err, &cfErr) {
+				t.Fatalf("Upsert() error = %v, want a *CloudflareError", err)
+			}
+			if cfErr.Status != tt.status || cfErr.Retryable() != tt.retryable {
+				t.Errorf("error status %v retryable %v, want %v and %v", cfErr.Status, cfErr.Retryable(), tt.status, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestCloudflareInvalidSRV(t *testing.T) {
+	p := newTestCloudflare(t, &fakeCloudflare{zone: "zone1", records: map[string]cloudflareRecord{}})
+
+	_, err := p.Upsert(context.Background(), "zone1", []Record{{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 mc.example.com"}})
+	if err == nil {
+		t.Error("Upsert() error = nil, want an error")
+	}
+}
+
+// assertCloudflareRecords checks the records of the fake API
+func assertCloudflareRecords(t *testing.T, fake *fakeCloudflare, want map[string]cloudflareRecord) {
+	t.Helper()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	got, _ := json.Marshal(fake.records)
+	wanted, _ := json.Marshal(want)
+	if string(got) != string(wanted) {
+		t.Errorf("records are\n%s\nwant\n%s", got, wanted)
+	}
+}
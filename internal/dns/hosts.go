@@ -0,0 +1,146 @@
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+)
+
+// hostsMarker ends the lines the Hosts provider manages, so other lines of the file are left alone
+const hostsMarker = "# seigetsu"
+
+// Hosts manages records in a hosts file, for testing without a DNS provider. The file is set when the provider is
+// created, and the zone is ignored so it can't be used to write other files. A and AAAA records are written as hosts
+// entries, so the system's resolver finds them, and other records as comments.
+type Hosts struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewHosts returns a provider that manages the records in the hosts file at path
+func NewHosts(path string) *Hosts {
+	return &Hosts{path: path}
+}
+
+// Upsert implements Provider. The file is changed at once, so there is no change ID.
+func (p *Hosts) Upsert(ctx context.Context, zone string, records []Record) (string, error) {
+	// Records are written as lines of the file, so they must not be able to add lines of their own
+	for _, record := range records {
+		if err := record.Validate(); err != nil {
+			return "", err
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines, err := readHosts(p.path)
+	if err != nil {
+		return "", err
+	}
+
+	lines = removeHosts(lines, records)
+	for _, record := range records {
+		lines = append(lines, hostsLine(record))
+	}
+
+	return "", writeHosts(p.path, lines)
+}
+
+// Delete implements Provider
+func (p *Hosts) Delete(ctx context.Context, zone string, records []Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	lines, err := readHosts(p.path)
+	if err != nil {
+		return err
+	}
+
+	return writeHosts(p.path, removeHosts(lines, records))
+}
+
+// Synced implements Provider. Changes to the file are live once they are made.
+func (p *Hosts) Synced(ctx context.Context, changeID string) (bool, error) {
+	return true, nil
+}
+
+// hostsLine returns the line of the record
+func hostsLine(record Record) string {
+	if record.Type == TypeA || record.Type == TypeAAAA {
+		return fmt.Sprintf("%v %v %v %v %v", record.Value, record.Name, hostsMarker, record.Type, record.TTL)
+	}
+
+	return fmt.Sprintf("# %v %v %v %v %v", record.Name, record.Type, record.TTL, record.Value, hostsMarker)
+}
+
+// parseHostsLine returns the name and type of a line the provider manages
+func parseHostsLine(line string) (name string, recordType string, ok bool) {
+	if !strings.Contains(line, hostsMarker) {
+		return "", "", false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return "", "", false
+	}
+	if fields[0] == "#" {
+		if len(fields) < 3 {
+			return "", "", false
+		}
+		return fields[1], fields[2], true
+	}
+
+	ip := net.ParseIP(fields[0])
+	if ip == nil {
+		return "", "", false
+	}
+	if ip.To4() != nil {
+		return fields[1], TypeA, true
+	}
+
+	return fields[1], TypeAAAA, true
+}
+
+// removeHosts returns the lines without the managed lines with the name and type of one of the records
+func removeHosts(lines []string, records []Record) []string {
+	var kept []string
+	for _, line := range lines {
+		if name, recordType, ok := parseHostsLine(line); ok && matches(name, recordType, records) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	return kept
+}
+
+// readHosts reads the lines of the file, which has none if it doesn't exist yet
+func readHosts(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n"), nil
+}
+
+// writeHosts replaces the file with the lines
+func writeHosts(path string, lines []string) error {
+	data := strings.Join(lines, "\n")
+	if len(lines) > 0 {
+		data += "\n"
+	}
+
+	return os.WriteFile(path, []byte(data), 0o644)
+}
@@ -0,0 +1,119 @@
+package dns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte("127.0.0.1 localhost\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := NewHosts(path)
+	ctx := context.Background()
+
+	records := []Record{
+		{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: 300},
+		{Name: "mc.example.com", Type: TypeAAAA, Value: "2001:db8::10", TTL: 300},
+		{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25566 mc.example.com", TTL: 300},
+	}
+	if _, err := p.Upsert(ctx, "ignored", records); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	// Upserting again replaces the records instead of adding more
+	records[0].Value = "203.0.113.20"
+	if _, err := p.Upsert(ctx, "ignored", records); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+
+	want := "127.0.0.1 localhost\n" +
+		"203.0.113.20 mc.example.com # seigetsu A 300\n" +
+		"2001:db8::10 mc.example.com # seigetsu AAAA 300\n" +
+		"# _minecraft._tcp.mc.example.com SRV 300 0 5 25566 mc.example.com # seigetsu\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("after Upsert() the file is\n%v\nwant\n%v", got, want)
+	}
+
+	if err := p.Delete(ctx, "ignored", []Record{{Name: "mc.example.com", Type: TypeA}, {Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV}}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	want = "127.0.0.1 localhost\n" +
+		"2001:db8::10 mc.example.com # seigetsu AAAA 300\n"
+	if got := readFile(t, path); got != want {
+		t.Errorf("after Delete() the file is\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestHostsRejectsInvalidRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+	}{
+		{name: "newline in name", record: Record{Name: "mc.example.com\n1.2.3.4 bank.example.com", Type: TypeA, Value: "203.0.113.10"}},
+		{name: "space in name", record: Record{Name: "mc example.com", Type: TypeA, Value: "203.0.113.10"}},
+		{name: "newline in value", record: Record{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10\n1.2.3.4 bank.example.com"}},
+		{name: "invalid IPv4", record: Record{Name: "mc.example.com", Type: TypeA, Value: "mc.example.com"}},
+		{name: "IPv6 in A record", record: Record{Name: "mc.example.com", Type: TypeA, Value: "2001:db8::10"}},
+		{name: "IPv4 in AAAA record", record: Record{Name: "mc.example.com", Type: TypeAAAA, Value: "203.0.113.10"}},
+		{name: "invalid SRV", record: Record{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 port mc.example.com"}},
+		{name: "SRV with extra lines", record: Record{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25565 mc.example.com\n#"}},
+		{name: "other type", record: Record{Name: "mc.example.com", Type: "TXT", Value: "hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hosts")
+			if _, err := NewHosts(path).Upsert(context.Background(), "", []Record{tt.record}); err == nil {
+				t.Error("Upsert() error = nil, want an error")
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("Upsert() wrote %v", path)
+			}
+		})
+	}
+}
+
+func TestRecordValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		record Record
+		valid  bool
+	}{
+		{name: "A", record: Record{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10"}, valid: true},
+		{name: "AAAA", record: Record{Name: "mc.example.com", Type: TypeAAAA, Value: "2001:db8::10"}, valid: true},
+		{name: "SRV", record: Record{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25565 mc.example.com."}, valid: true},
+		{name: "single label", record: Record{Name: "localhost", Type: TypeA, Value: "127.0.0.1"}, valid: true},
+		{name: "empty name", record: Record{Type: TypeA, Value: "203.0.113.10"}},
+		{name: "leading hyphen", record: Record{Name: "-mc.example.com", Type: TypeA, Value: "203.0.113.10"}},
+		{name: "empty label", record: Record{Name: "mc..example.com", Type: TypeA, Value: "203.0.113.10"}},
+		{name: "long label", record: Record{Name: strings.Repeat("a", 64) + ".example.com", Type: TypeA, Value: "203.0.113.10"}},
+		{name: "negative TTL", record: Record{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: -1}},
+		{name: "SRV port out of range", record: Record{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 70000 mc.example.com"}},
+		{name: "SRV invalid target", record: Record{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25565 /etc/passwd"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.record.Validate(); (err == nil) != tt.valid {
+				t.Errorf("Validate() error = %v, want valid %v", err, tt.valid)
+			}
+		})
+	}
+}
+
+// readFile returns the contents of the file
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,99 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Types of the records the bot manages
+const (
+	TypeA    = "A"
+	TypeAAAA = "AAAA"
+	TypeSRV  = "SRV"
+)
+
+// Record is a DNS record. Names have no trailing dot and SRV values are "<priority> <weight> <port> <target>".
+type Record struct {
+	Name  string
+	Type  string
+	Value string
+	TTL   int64
+}
+
+// hostname matches a DNS name without a trailing dot. Labels can start with an underscore, like in SRV names.
+var hostname = regexp.MustCompile(`^(?i)(?:[a-z0-9_](?:[a-z0-9_-]{0,61}[a-z0-9])?\.)*[a-z0-9_](?:[a-z0-9_-]{0,61}[a-z0-9])?$`)
+
+// Validate checks that the record is a valid A, AAAA or SRV record
+func (r Record) Validate() error {
+	if len(r.Name) > 253 || !hostname.MatchString(r.Name) {
+		return fmt.Errorf("invalid record name %q", r.Name)
+	}
+	if r.TTL < 0 {
+		return fmt.Errorf("invalid TTL %v of %v", r.TTL, r.Name)
+	}
+
+	switch r.Type {
+	case TypeA:
+		if ip := net.ParseIP(r.Value); ip == nil || ip.To4() == nil {
+			return fmt.Errorf("invalid IPv4 address %q of %v", r.Value, r.Name)
+		}
+	case TypeAAAA:
+		if ip := net.ParseIP(r.Value); ip == nil || ip.To4() != nil {
+			return fmt.Errorf("invalid IPv6 address %q of %v", r.Value, r.Name)
+		}
+	case TypeSRV:
+		fields := strings.Fields(r.Value)
+		if len(fields) != 4 || strings.Join(fields, " ") != r.Value {
+			return fmt.Errorf("invalid SRV value %q of %v", r.Value, r.Name)
+		}
+		for _, field := range fields[:3] {
+			if _, err := strconv.ParseUint(field, 10, 16); err != nil {
+				return fmt.Errorf("invalid SRV value %q of %v", r.Value, r.Name)
+			}
+		}
+		if target := strings.TrimSuffix(fields[3], "."); len(target) > 253 || !hostname.MatchString(target) {
+			return fmt.Errorf("invalid SRV target %q of %v", fields[3], r.Name)
+		}
+	default:
+		return fmt.Errorf("unsupported record type %q of %v", r.Type, r.Name)
+	}
+
+	return nil
+}
+
+// Names of the providers
+const (
+	ProviderRoute53    = "route53"
+	ProviderCloudflare = "cloudflare"
+	ProviderHosts      = "hosts"
+)
+
+// Provider manages the DNS records of a zone. What the zone is depends on the provider, like a Route53 hosted zone
+// ID or a Cloudflare zone ID.
+type Provider interface {
+	// Upsert creates the records, replacing the records with the same name and type. It returns the ID of the change
+	// to pass to Synced, which is empty if the change applies at once.
+	Upsert(ctx context.Context, zone string, records []Record) (string, error)
+
+	// Delete deletes every record with the name and type of one of the records, whatever its value
+	Delete(ctx context.Context, zone string, records []Record) error
+
+	// Synced reports whether the change has reached all of the provider's name servers
+	Synced(ctx context.Context, changeID string) (bool, error)
+}
+
+// matches reports whether the record has the name and type of one of the records
+func matches(name string, recordType string, records []Record) bool {
+	name = strings.TrimSuffix(name, ".")
+	for _, record := range records {
+		if strings.EqualFold(record.Name, name) && strings.EqualFold(record.Type, recordType) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package dns
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+)
+
+// Route53 manages the records of a Route53 hosted zone. The zone is the hosted zone ID.
+type Route53 struct {
+	client *route53.Client
+}
+
+// NewRoute53 returns a Route53 provider. ROUTE53_ENDPOINT replaces the Route53 API URL, like for a local stand-in.
+func NewRoute53(cfg aws.Config) *Route53 {
+	return &Route53{
+		client: route53.NewFromConfig(cfg, func(o *route53.Options) {
+			// Callers retry the calls, so the SDK doesn't retry them as well
+			o.RetryMaxAttempts = 1
+			if endpoint, ok := os.LookupEnv("ROUTE53_ENDPOINT"); ok {
+				o.BaseEndpoint = aws.String(endpoint)
+			}
+		}),
+	}
+}
+
+// Upsert implements Provider
+func (p *Route53) Upsert(ctx context.Context, zone string, records []Record) (string, error) {
+	var changes []route53Types.Change
+	for _, record := range records {
+		changes = append(changes, route53Types.Change{
+			Action: route53Types.ChangeActionUpsert,
+			ResourceRecordSet: &route53Types.ResourceRecordSet{
+				Name:            aws.String(record.Name),
+				Type:            route53Types.RRType(record.Type),
+				TTL:             aws.Int64(record.TTL),
+				ResourceRecords: []route53Types.ResourceRecord{{Value: aws.String(route53Value(record))}},
+			},
+		})
+	}
+
+	output, err := p.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zone),
+		ChangeBatch:  &route53Types.ChangeBatch{Changes: changes},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return aws.ToString(output.ChangeInfo.Id), nil
+}
+
+// Delete implements Provider
+func (p *Route53) Delete(ctx context.Context, zone string, records []Record) error {
+	// Route53 only deletes record sets that match exactly, so look up what they are now
+	var changes []route53Types.Change
+	listed := make(map[string]bool)
+	deleted := make(map[string]bool)
+	for _, record := range records {
+		if listed[strings.ToLower(record.Name)] {
+			continue
+		}
+		listed[strings.ToLower(record.Name)] = true
+
+		output, err := p.client.ListResourceRecordSets(ctx, &route53.ListResourceRecordSetsInput{
+			HostedZoneId:    aws.String(zone),
+			StartRecordName: aws.String(record.Name),
+			MaxItems:        aws.Int32(10),
+		})
+		if err != nil {
+			return err
+		}
+
+		for n, set := range output.ResourceRecordSets {
+			// Listing a name also returns the names below it, which may have been listed already
+			key := strings.ToLower(strings.TrimSuffix(aws.ToString(set.Name), ".")) + " " + string(set.Type)
+			if deleted[key] || !matches(aws.ToString(set.Name), string(set.Type), records) {
+				continue
+			}
+			deleted[key] = true
+			changes = append(changes, route53Types.Change{
+				Action:            route53Types.ChangeActionDelete,
+				ResourceRecordSet: &output.ResourceRecordSets[n],
+			})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	_, err := p.client.ChangeResourceRecordSets(ctx, &route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(zone),
+		ChangeBatch:  &route53Types.ChangeBatch{Changes: changes},
+	})
+
+	return err
+}
+
+// Synced implements Provider. Route53 changes are PENDING until every name server has them.
+func (p *Route53) Synced(ctx context.Context, changeID string) (bool, error) {
+	if changeID == "" {
+		return true, nil
+	}
+
+	output, err := p.client.GetChange(ctx, &route53.GetChangeInput{Id: aws.String(changeID)})
+	if err != nil {
+		return false, err
+	}
+
+	return output.ChangeInfo.Status == route53Types.ChangeStatusInsync, nil
+}
+
+// route53Value returns the value of the record as Route53 takes it, with the SRV target fully qualified
+func route53Value(record Record) string {
+	if record.Type == TypeSRV && !strings.HasSuffix(record.Value, ".") {
+		return record.Value + "."
+	}
+
+	return record.Value
+}
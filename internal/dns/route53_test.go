@@ -0,0 +1,225 @@
+package dns
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// route53RecordSet is a record set in the Route53 API
+type route53RecordSet struct {
+	Name   string   `xml:"Name"`
+	Type   string   `xml:"Type"`
+	TTL    int64    `xml:"TTL"`
+	Values []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+// fakeRoute53 is a stand-in for the Route53 API with one hosted zone
+type fakeRoute53 struct {
+	mu      sync.Mutex
+	zone    string
+	sets    map[string]route53RecordSet
+	changes int
+
+	// synced is whether changes are reported as INSYNC
+	synced bool
+}
+
+func (f *fakeRoute53) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rrset := "/2013-04-01/hostedzone/" + f.zone + "/rrset"
+	switch {
+	case r.Method == http.MethodPost && strings.TrimSuffix(r.URL.Path, "/") == rrset:
+		var request struct {
+			Changes []struct {
+				Action string           `xml:"Action"`
+				Set    route53RecordSet `xml:"ResourceRecordSet"`
+			} `xml:"ChangeBatch>Changes>Change"`
+		}
+		if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeRoute53Error(w, http.StatusBadRequest, "InvalidInput", err.Error())
+			return
+		}
+
+		for _, change := range request.Changes {
+			// Route53 returns names fully qualified
+			change.Set.Name = strings.TrimSuffix(change.Set.Name, ".") + "."
+			key := route53Key(change.Set.Name, change.Set.Type)
+			switch change.Action {
+			case "UPSERT":
+				f.sets[key] = change.Set
+			case "DELETE":
+				if !reflect.DeepEqual(f.sets[key], change.Set) {
+					writeRoute53Error(w, http.StatusBadRequest, "InvalidChangeBatch", "record set not found: "+key)
+					return
+				}
+				delete(f.sets, key)
+			}
+		}
+		f.changes++
+
+		writeRoute53(w, fmt.Sprintf(`<ChangeResourceRecordSetsResponse><ChangeInfo><Id>/change/C%d</Id><Status>PENDING</Status><SubmittedAt>2026-10-19T00:00:00Z</SubmittedAt></ChangeInfo></ChangeResourceRecordSetsResponse>`, f.changes))
+	case r.Method == http.MethodGet && r.URL.Path == rrset:
+		start := route53Key(r.URL.Query().Get("name"), "")
+		var keys []string
+		for key := range f.sets {
+			if key >= start {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		var response struct {
+			XMLName     xml.Name           `xml:"ListResourceRecordSetsResponse"`
+			Sets        []route53RecordSet `xml:"ResourceRecordSets>ResourceRecordSet"`
+			IsTruncated bool               `xml:"IsTruncated"`
+			MaxItems    int                `xml:"MaxItems"`
+		}
+		response.MaxItems = 10
+		for _, key := range keys {
+			response.Sets = append(response.Sets, f.sets[key])
+		}
+		data, _ := xml.Marshal(response)
+		writeRoute53(w, string(data))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/2013-04-01/change/"):
+		status := "PENDING"
+		if f.synced {
+			status = "INSYNC"
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/2013-04-01/change/")
+		writeRoute53(w, fmt.Sprintf(`<GetChangeResponse><ChangeInfo><Id>/change/%v</Id><Status>%v</Status><SubmittedAt>2026-10-19T00:00:00Z</SubmittedAt></ChangeInfo></GetChangeResponse>`, id, status))
+	default:
+		writeRoute53Error(w, http.StatusNotFound, "NoSuchHostedZone", "No hosted zone found at "+r.URL.Path)
+	}
+}
+
+// route53Key sorts record sets like Route53 does, by name with the labels reversed and then by type
+func route53Key(name string, recordType string) string {
+	labels := strings.Split(strings.TrimSuffix(strings.ToLower(name), "."), ".")
+	for a, b := 0, len(labels)-1; a < b; a, b = a+1, b-1 {
+		labels[a], labels[b] = labels[b], labels[a]
+	}
+
+	return strings.Join(labels, ".") + " " + recordType
+}
+
+// writeRoute53 writes a response of the Route53 API
+func writeRoute53(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprint(w, xml.Header+body)
+}
+
+// writeRoute53Error writes an error response of the Route53 API
+func writeRoute53Error(w http.ResponseWriter, status int, code string, message string) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.WriteHeader(status)
+	fmt.Fprintf(w, `%v<ErrorResponse><Error><Type>Sender</Type><Code>%v</Code><Message>%v</Message></Error><RequestId>1</RequestId></ErrorResponse>`, xml.Header, code, message)
+}
+
+// newTestRoute53 returns a provider that uses the fake API
+func newTestRoute53(t *testing.T, handler http.Handler) *Route53 {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("ROUTE53_ENDPOINT", server.URL)
+
+	return NewRoute53(aws.Config{
+		Region:           "us-east-1",
+		Credentials:      aws.AnonymousCredentials{},
+		RetryMaxAttempts: 1,
+	})
+}
+
+func TestRoute53(t *testing.T) {
+	fake := &fakeRoute53{
+		zone: "Z123",
+		sets: map[string]route53RecordSet{
+			route53Key("www.example.com", TypeA): {Name: "www.example.com.", Type: TypeA, TTL: 60, Values: []string{"198.51.100.3"}},
+		},
+	}
+	p := newTestRoute53(t, fake)
+	ctx := context.Background()
+
+	records := []Record{
+		{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: 300},
+		{Name: "mc.example.com", Type: TypeAAAA, Value: "2001:db8::10", TTL: 300},
+		{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV, Value: "0 5 25566 mc.example.com", TTL: 300},
+	}
+	changeID, err := p.Upsert(ctx, "Z123", records)
+	if err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	if changeID != "/change/C1" {
+		t.Errorf("Upsert() change ID = %q, want /change/C1", changeID)
+	}
+
+	want := map[string]route53RecordSet{
+		route53Key("www.example.com", TypeA):                  {Name: "www.example.com.", Type: TypeA, TTL: 60, Values: []string{"198.51.100.3"}},
+		route53Key("mc.example.com", TypeA):                   {Name: "mc.example.com.", Type: TypeA, TTL: 300, Values: []string{"203.0.113.10"}},
+		route53Key("mc.example.com", TypeAAAA):                {Name: "mc.example.com.", Type: TypeAAAA, TTL: 300, Values: []string{"2001:db8::10"}},
+		route53Key("_minecraft._tcp.mc.example.com", TypeSRV): {Name: "_minecraft._tcp.mc.example.com.", Type: TypeSRV, TTL: 300, Values: []string{"0 5 25566 mc.example.com."}},
+	}
+	assertRoute53Sets(t, fake, want)
+
+	for _, synced := range []bool{false, true} {
+		fake.mu.Lock()
+		fake.synced = synced
+		fake.mu.Unlock()
+
+		got, err := p.Synced(ctx, changeID)
+		if err != nil || got != synced {
+			t.Errorf("Synced() = %v, %v, want %v", got, err, synced)
+		}
+	}
+
+	// Deleting finds the record sets whatever their values are
+	err = p.Delete(ctx, "Z123", []Record{
+		{Name: "mc.example.com", Type: TypeA},
+		{Name: "mc.example.com", Type: TypeAAAA},
+		{Name: "_minecraft._tcp.mc.example.com", Type: TypeSRV},
+	})
+	if err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	assertRoute53Sets(t, fake, map[string]route53RecordSet{route53Key("www.example.com", TypeA): want[route53Key("www.example.com", TypeA)]})
+
+	// Deleting records that are already gone changes nothing
+	if err := p.Delete(ctx, "Z123", []Record{{Name: "mc.example.com", Type: TypeA}}); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if fake.changes != 2 {
+		t.Errorf("made %v changes, want 2", fake.changes)
+	}
+}
+
+func TestRoute53Errors(t *testing.T) {
+	p := newTestRoute53(t, &fakeRoute53{zone: "Z123", sets: map[string]route53RecordSet{}})
+
+	if _, err := p.Upsert(context.Background(), "Z999", []Record{{Name: "mc.example.com", Type: TypeA, Value: "203.0.113.10", TTL: 300}}); err == nil || !strings.Contains(err.Error(), "NoSuchHostedZone") {
+		t.Errorf("Upsert() error = %v, want NoSuchHostedZone", err)
+	}
+}
+
+// assertRoute53Sets checks the record sets of the fake API
+func assertRoute53Sets(t *testing.T, fake *fakeRoute53, want map[string]route53RecordSet) {
+	t.Helper()
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+
+	if !reflect.DeepEqual(fake.sets, want) {
+		t.Errorf("record sets are\n%+v\nwant\n%+v", fake.sets, want)
+	}
+}
@@ -1,48 +1,148 @@
 package mcstatus
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const pingTimeout = 5 * time.Second
+
+// apiURL is the mcstatus.io API. MCSTATUS_API_URL replaces it, like for a local stand-in.
+var apiURL = "https://api.mcstatus.io/v2"
+
+func init() {
+	if url, ok := os.LookupEnv("MCSTATUS_API_URL"); ok {
+		apiURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// StatusError is an unsuccessful response from mcstatus.io
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("mcstatus.io responded with %v %v", e.Code, http.StatusText(e.Code))
+}
+
+// Retryable reports whether the request might succeed later, which is when mcstatus.io is rate limiting or failing
+func (e *StatusError) Retryable() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
 type MCStatusResponse struct {
-	Online  bool `json:"online"`
+	Online  bool   `json:"online"`
+	Host    string `json:"host"`
+	Port    int    `json:"port"`
+	Version struct {
+		NameClean string `json:"name_clean"`
+	} `json:"version"`
 	Players struct {
 		Online int `json:"online"`
+		Max    int `json:"max"`
+		List   []struct {
+			NameClean string `json:"name_clean"`
+		} `json:"list"`
 	} `json:"players"`
+	MOTD struct {
+		Clean string `json:"clean"`
+	} `json:"motd"`
+	Icon string `json:"icon"`
 }
 
-// GetMCStatus checks with mcstatus.io to get information about the Minecraft server
-func GetMCStatus() (bool, int, error) {
-	serverURL := fmt.Sprintf("%v.%v", os.Getenv("PIXELMON_SUBDOMAIN"), os.Getenv("PIXELMON_DOMAIN"))
-	if serverURL == "" {
-		return false, 0, fmt.Errorf("PIXELMON_DOMAIN/PIXELMON_SUBDOMAIN environment variable not set")
+// GetMCStatus checks with mcstatus.io to get information about the Minecraft server at host
+func GetMCStatus(ctx context.Context, host string) (bool, int, error) {
+	status, err := GetMCStatusResponse(ctx, host)
+	if err != nil {
+		return false, 0, err
 	}
 
-	url := fmt.Sprintf("https://api.mcstatus.io/v2/status/java/%s", serverURL)
+	return status.Online, status.Players.Online, nil
+}
 
-	resp, err := http.Get(url)
+// GetPlayerNames returns the names of the players currently online on the Minecraft server at host
+func GetPlayerNames(ctx context.Context, host string) ([]string, error) {
+	status, err := GetMCStatusResponse(ctx, host)
 	if err != nil {
-		return false, 0, err
+		return nil, err
+	}
+
+	names := make([]string, 0, len(status.Players.List))
+	for _, player := range status.Players.List {
+		names = append(names, player.NameClean)
+	}
+
+	return names, nil
+}
+
+// GetFavicon decodes the server icon from the response into PNG bytes
+func (r *MCStatusResponse) GetFavicon() ([]byte, error) {
+	data, found := strings.CutPrefix(r.Icon, "data:image/png;base64,")
+	if !found {
+		return nil, fmt.Errorf("server icon is not a base64 encoded PNG")
+	}
+
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// Ping measures the time it takes to open a connection to the Minecraft server
+func Ping(ctx context.Context, host string, port int) (time.Duration, error) {
+	dialer := net.Dialer{Timeout: pingTimeout}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	return time.Since(start), nil
+}
+
+// GetMCStatusResponse gets the full response from mcstatus.io for the Minecraft server at host
+func GetMCStatusResponse(ctx context.Context, host string) (*MCStatusResponse, error) {
+	if host == "" || strings.HasPrefix(host, ".") || strings.HasSuffix(host, ".") {
+		return nil, fmt.Errorf("domain or subdomain of the server not set")
+	}
+
+	url := fmt.Sprintf("%s/status/java/%s", apiURL, host)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{Code: resp.StatusCode}
+	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return false, 0, err
+		return nil, err
 	}
 
 	var status MCStatusResponse
 	err = json.Unmarshal(body, &status)
 	if err != nil {
-		return false, 0, err
+		return nil, err
 	}
 
-	log.Printf("%v | Online: %v, Player Count: %v", serverURL, status.Online, status.Players.Online)
+	log.Printf("%v | Online: %v, Player Count: %v", host, status.Online, status.Players.Online)
 
-	return status.Online, status.Players.Online, nil
+	return &status, nil
 }
@@ -0,0 +1,192 @@
+package permissions
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Names maps the Discord permissions that rules can require to their bits
+var Names = map[string]int64{
+	"administrator":    discordgo.PermissionAdministrator,
+	"manage_server":    discordgo.PermissionManageServer,
+	"manage_channels":  discordgo.PermissionManageChannels,
+	"manage_roles":     discordgo.PermissionManageRoles,
+	"manage_messages":  discordgo.PermissionManageMessages,
+	"kick_members":     discordgo.PermissionKickMembers,
+	"ban_members":      discordgo.PermissionBanMembers,
+	"moderate_members": discordgo.PermissionModerateMembers,
+}
+
+// Rule decides who can use a subcommand. Deny entries take precedence over allow entries and a rule without any
+// allow entries allows everyone who isn't denied. Roles can be given by ID or by name.
+type Rule struct {
+	Roles       []string `json:"roles,omitempty"`
+	Users       []string `json:"users,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	DenyRoles   []string `json:"deny_roles,omitempty"`
+	DenyUsers   []string `json:"deny_users,omitempty"`
+}
+
+// Member is the user trying to use a subcommand
+type Member struct {
+	UserID string
+
+	// Roles has both the IDs and the names of the member's roles
+	Roles []string
+
+	Permissions int64
+}
+
+// Policy maps subcommands to their rules
+type Policy map[string]Rule
+
+// Default returns the policy used for subcommands without a rule of their own
+func Default() Policy {
+	return Policy{
+		"start":      {Roles: []string{"Minecrafters"}},
+		"stop":       {Roles: []string{"Minecrafters"}},
+		"restart":    {Roles: []string{"Minecrafters"}},
+		"whitelist":  {Roles: []string{"Minecrafters"}},
+		"say":        {Roles: []string{"Minecrafters"}},
+		"online":     {Roles: []string{"Minecrafters"}},
+		"status":     {Roles: []string{"Minecrafters"}},
+		"dashboard":  {Permissions: []string{"administrator"}},
+		"resize":     {Permissions: []string{"administrator"}},
+		"properties": {Permissions: []string{"administrator"}},
+		"gamerule":   {Permissions: []string{"administrator"}},
+	}
+}
+
+// IsEmpty checks if the rule has no entries
+func (r Rule) IsEmpty() bool {
+	return len(r.Roles) == 0 && len(r.Users) == 0 && len(r.Permissions) == 0 && len(r.DenyRoles) == 0 && len(r.DenyUsers) == 0
+}
+
+// Allows checks if the member can use the subcommand
+func (r Rule) Allows(m Member) bool {
+	if contains(r.DenyUsers, m.UserID) || containsAny(r.DenyRoles, m.Roles) {
+		return false
+	}
+
+	if len(r.Roles) == 0 && len(r.Users) == 0 && len(r.Permissions) == 0 {
+		return true
+	}
+
+	if contains(r.Users, m.UserID) || containsAny(r.Roles, m.Roles) {
+		return true
+	}
+	for _, name := range r.Permissions {
+		if bit, ok := Names[name]; ok && m.Permissions&bit != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allow adds entries to the allow lists and removes them from the deny lists
+func (r Rule) Allow(roles []string, users []string, permissions []string) Rule {
+	r.Roles = add(r.Roles, roles...)
+	r.Users = add(r.Users, users...)
+	r.Permissions = add(r.Permissions, permissions...)
+	r.DenyRoles = remove(r.DenyRoles, roles...)
+	r.DenyUsers = remove(r.DenyUsers, users...)
+
+	return r
+}
+
+// Deny adds entries to the deny lists and removes them from the allow lists
+func (r Rule) Deny(roles []string, users []string) Rule {
+	r.DenyRoles = add(r.DenyRoles, roles...)
+	r.DenyUsers = add(r.DenyUsers, users...)
+	r.Roles = remove(r.Roles, roles...)
+	r.Users = remove(r.Users, users...)
+
+	return r
+}
+
+// Remove removes entries from every list
+func (r Rule) Remove(roles []string, users []string, permissions []string) Rule {
+	r.Roles = remove(r.Roles, roles...)
+	r.Users = remove(r.Users, users...)
+	r.Permissions = remove(r.Permissions, permissions...)
+	r.DenyRoles = remove(r.DenyRoles, roles...)
+	r.DenyUsers = remove(r.DenyUsers, users...)
+
+	return r
+}
+
+// Rule returns the rule for the subcommand, falling back to the default policy
+func (p Policy) Rule(subcommand string) Rule {
+	if rule, ok := p[subcommand]; ok {
+		return rule
+	}
+
+	return Default()[subcommand]
+}
+
+// Subcommands returns the subcommands with a rule in the policy or the default policy, sorted
+func (p Policy) Subcommands() []string {
+	seen := make(map[string]bool)
+	for subcommand := range Default() {
+		seen[subcommand] = true
+	}
+	for subcommand := range p {
+		seen[subcommand] = true
+	}
+
+	subcommands := make([]string, 0, len(seen))
+	for subcommand := range seen {
+		subcommands = append(subcommands, subcommand)
+	}
+	sort.Strings(subcommands)
+
+	return subcommands
+}
+
+// contains checks if the list has the value, ignoring case
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsAny checks if the list has any of the values, ignoring case
+func containsAny(list []string, values []string) bool {
+	for _, value := range values {
+		if contains(list, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add appends the values that aren't in the list yet
+func add(list []string, values ...string) []string {
+	for _, value := range values {
+		if !contains(list, value) {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
+
+// remove drops the values from the list
+func remove(list []string, values ...string) []string {
+	var kept []string
+	for _, v := range list {
+		if !contains(values, v) {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
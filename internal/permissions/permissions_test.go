@@ -0,0 +1,136 @@
+package permissions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func TestRuleAllows(t *testing.T) {
+	member := Member{
+		UserID:      "1",
+		Roles:       []string{"10", "Minecrafters"},
+		Permissions: discordgo.PermissionManageServer,
+	}
+
+	tests := []struct {
+		name string
+		rule Rule
+		want bool
+	}{
+		{name: "empty rule", rule: Rule{}, want: true},
+		{name: "role by name", rule: Rule{Roles: []string{"minecrafters"}}, want: true},
+		{name: "role by ID", rule: Rule{Roles: []string{"10"}}, want: true},
+		{name: "other role", rule: Rule{Roles: []string{"Admins"}}, want: false},
+		{name: "user", rule: Rule{Users: []string{"1"}}, want: true},
+		{name: "other user", rule: Rule{Users: []string{"2"}}, want: false},
+		{name: "permission", rule: Rule{Permissions: []string{"manage_server"}}, want: true},
+		{name: "missing permission", rule: Rule{Permissions: []string{"administrator"}}, want: false},
+		{name: "unknown permission", rule: Rule{Permissions: []string{"fly"}}, want: false},
+		{name: "denied role", rule: Rule{DenyRoles: []string{"Minecrafters"}}, want: false},
+		{name: "denied user", rule: Rule{DenyUsers: []string{"1"}}, want: false},
+		{name: "deny wins over allow", rule: Rule{Users: []string{"1"}, DenyRoles: []string{"10"}}, want: false},
+		{name: "deny others only", rule: Rule{DenyUsers: []string{"2"}}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Allows(member); got != tt.want {
+				t.Errorf("%+v.Allows() = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(Rule) Rule
+		rule   Rule
+		want   Rule
+	}{
+		{
+			name:   "allow",
+			change: func(r Rule) Rule { return r.Allow([]string{"Minecrafters"}, []string{"1"}, []string{"manage_server"}) },
+			rule:   Rule{Roles: []string{"minecrafters"}, DenyRoles: []string{"Minecrafters", "Guests"}, DenyUsers: []string{"1"}},
+			want:   Rule{Roles: []string{"minecrafters"}, Users: []string{"1"}, Permissions: []string{"manage_server"}, DenyRoles: []string{"Guests"}},
+		},
+		{
+			name:   "deny",
+			change: func(r Rule) Rule { return r.Deny([]string{"Guests"}, []string{"1"}) },
+			rule:   Rule{Roles: []string{"Guests", "Minecrafters"}, Users: []string{"1"}},
+			want:   Rule{Roles: []string{"Minecrafters"}, DenyRoles: []string{"Guests"}, DenyUsers: []string{"1"}},
+		},
+		{
+			name:   "remove",
+			change: func(r Rule) Rule { return r.Remove([]string{"Guests"}, []string{"1"}, []string{"administrator"}) },
+			rule:   Rule{Roles: []string{"Guests"}, Permissions: []string{"administrator"}, DenyUsers: []string{"1"}},
+			want:   Rule{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.change(tt.rule)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if tt.want.IsEmpty() != got.IsEmpty() {
+				t.Errorf("IsEmpty() = %v, want %v", got.IsEmpty(), tt.want.IsEmpty())
+			}
+		})
+	}
+}
+
+func TestPolicyRule(t *testing.T) {
+	policy := Policy{
+		"start": {Users: []string{"1"}},
+		"say":   {},
+	}
+
+	tests := []struct {
+		subcommand string
+		want       Rule
+	}{
+		{subcommand: "start", want: Rule{Users: []string{"1"}}},
+		{subcommand: "say", want: Rule{}},
+		{subcommand: "stop", want: Rule{Roles: []string{"Minecrafters"}}},
+		{subcommand: "status", want: Rule{Roles: []string{"Minecrafters"}}},
+		{subcommand: "resize", want: Rule{Permissions: []string{"administrator"}}},
+		{subcommand: "help", want: Rule{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subcommand, func(t *testing.T) {
+			if got := policy.Rule(tt.subcommand); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Rule(%q) = %+v, want %+v", tt.subcommand, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	minecrafter := Member{UserID: "1", Roles: []string{"Minecrafters"}}
+	everyone := Member{UserID: "2"}
+
+	for _, subcommand := range []string{"start", "stop", "restart", "whitelist", "say", "online", "status"} {
+		rule := Default().Rule(subcommand)
+		if !rule.Allows(minecrafter) || rule.Allows(everyone) {
+			t.Errorf("%v should only be allowed for Minecrafters by default", subcommand)
+		}
+	}
+	for _, subcommand := range []string{"dashboard", "resize", "properties", "gamerule"} {
+		if Default().Rule(subcommand).Allows(minecrafter) {
+			t.Errorf("%v should require administrator by default", subcommand)
+		}
+	}
+}
+
+func TestPolicySubcommands(t *testing.T) {
+	got := Policy{"help": {}, "start": {}}.Subcommands()
+	want := []string{"dashboard", "gamerule", "help", "online", "properties", "resize", "restart", "say", "start", "status", "stop", "whitelist"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Subcommands() = %v, want %v", got, want)
+	}
+}
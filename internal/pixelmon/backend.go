@@ -0,0 +1,114 @@
+package pixelmon
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// Backends the server can run on
+const (
+	BackendEC2     = "ec2"
+	BackendDocker  = "docker"
+	BackendProcess = "process"
+)
+
+// States of an instance, named like EC2's
+const (
+	instancePending  = "pending"
+	instanceRunning  = "running"
+	instanceStopping = "stopping"
+	instanceStopped  = "stopped"
+)
+
+// containerName matches the names and IDs of Docker containers, which can't be mistaken for options of the docker
+// command
+var containerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// localStopTimeout is how long a container or process has to save the world before it is killed
+const localStopTimeout = time.Minute
+
+// instance is the EC2 instance, container or process the server runs on
+type instance struct {
+	ID         string
+	State      string
+	Type       string
+	PublicIP   string
+	IPv6       string
+	LaunchTime time.Time
+
+	// Spot is set for EC2 spot instances, which can be interrupted. Hibernation is set for EC2 instances that can
+	// hibernate, and Hibernated for those that are stopped because they hibernated. Paused is set for paused Docker
+	// containers, which are stopped until they are unpaused.
+	Spot        bool
+	Hibernation bool
+	Hibernated  bool
+	Paused      bool
+}
+
+// address returns the address the server's hostname should resolve to, which is the IPv6 address if the instance has
+// no public IPv4 address
+func (i instance) address() string {
+	if i.PublicIP != "" {
+		return i.PublicIP
+	}
+
+	return i.IPv6
+}
+
+// backend runs the server's instance and the Minecraft service on it
+type backend interface {
+	// describe returns the instance, or ErrNotFound if it doesn't exist
+	describe(ctx context.Context) (instance, error)
+
+	// start and stop turn the instance on and off
+	start(ctx context.Context) error
+	stop(ctx context.Context) error
+
+	// startService starts the Minecraft service on the running instance. restartService starts it once the stopped
+	// service has exited.
+	startService(ctx context.Context) error
+	restartService(ctx context.Context) error
+
+	// console runs Minecraft commands on the service. query runs one and returns its response, or is ErrNotSupported
+	// if the backend can't read responses.
+	console(ctx context.Context, commands ...string) error
+	query(ctx context.Context, command string) (string, error)
+
+	// readFile and writeFile read and replace the contents of a file in the server's directory
+	readFile(ctx context.Context, name string) (string, error)
+	writeFile(ctx context.Context, name string, contents string) error
+
+	// shell runs a shell script in the server's directory and returns its output
+	shell(ctx context.Context, script string) (string, error)
+}
+
+// getBackend returns the backend the server runs on
+func getBackend(ctx context.Context, srv Server) (backend, error) {
+	switch srv.Backend {
+	case "", BackendEC2:
+		cfg, err := getConfig(ctx, srv)
+		if err != nil {
+			return nil, err
+		}
+		b := newEC2Backend(cfg, srv)
+		if err := b.resolveInstanceID(ctx); err != nil {
+			return nil, err
+		}
+		return b, nil
+	case BackendDocker:
+		if !containerName.MatchString(srv.InstanceID) {
+			return nil, fmt.Errorf("invalid container name %q", srv.InstanceID)
+		}
+		return dockerBackend{srv: srv}, nil
+	case BackendProcess:
+		if !filepath.IsAbs(srv.InstanceID) {
+			return nil, fmt.Errorf("directory %q isn't an absolute path", srv.InstanceID)
+		}
+		return processBackend{srv: srv}, nil
+	}
+
+	return nil, fmt.Errorf("unknown backend %q", srv.Backend)
+}
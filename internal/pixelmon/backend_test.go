@@ -0,0 +1,34 @@
+package pixelmon
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGetBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		srv     Server
+		wantErr bool
+	}{
+		{name: "container name", srv: Server{Backend: BackendDocker, InstanceID: "pixelmon"}},
+		{name: "container name with dots", srv: Server{Backend: BackendDocker, InstanceID: "pixelmon_1.16-a"}},
+		{name: "container ID", srv: Server{Backend: BackendDocker, InstanceID: "4f66ad9a0b2e"}},
+		{name: "empty container", srv: Server{Backend: BackendDocker}, wantErr: true},
+		{name: "container option", srv: Server{Backend: BackendDocker, InstanceID: "--privileged"}, wantErr: true},
+		{name: "container with space", srv: Server{Backend: BackendDocker, InstanceID: "pixelmon -v"}, wantErr: true},
+		{name: "container path", srv: Server{Backend: BackendDocker, InstanceID: "../pixelmon"}, wantErr: true},
+		{name: "directory", srv: Server{Backend: BackendProcess, InstanceID: "/srv/pixelmon"}},
+		{name: "relative directory", srv: Server{Backend: BackendProcess, InstanceID: "pixelmon"}, wantErr: true},
+		{name: "empty directory", srv: Server{Backend: BackendProcess}, wantErr: true},
+		{name: "unknown backend", srv: Server{Backend: "vm", InstanceID: "pixelmon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := getBackend(context.Background(), tt.srv); (err != nil) != tt.wantErr {
+				t.Errorf("getBackend() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
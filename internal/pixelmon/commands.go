@@ -2,322 +2,587 @@ package pixelmon
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
-	"os"
+	"slices"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
 	"github.com/kn-lim/seigetsu-bot/internal/mcstatus"
 )
 
-// GetStatus returns whether the EC2 instance is online or offline
-func GetStatus() (string, error) {
-	// Setup AWS Session
-	cfg, err := getConfig()
+// GetStatus returns whether the server's instance is running
+func GetStatus(ctx context.Context, srv Server) (bool, error) {
+	b, err := getBackend(ctx, srv)
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	// Get Pixelmon EC2 instance
-	_, instance, err := getPixelmonServer(cfg)
+	// Get Pixelmon instance
+	instance, err := b.describe(ctx)
 	if err != nil {
-		return "", err
+		return false, err
 	}
 
-	// Get Pixelmon EC2 instance status
-	if instance.State.Name == "running" {
-		return Message[Online], nil
-	}
-	return Message[Offline], nil
+	// Get Pixelmon instance status
+	return instance.State == instanceRunning, nil
 }
 
-// Start turns on the EC2 instance
-func Start() error {
-	log.Println("Starting Pixelmon EC2 instance...")
+// ServerInfo is a snapshot of the Pixelmon instance and Minecraft service
+type ServerInfo struct {
+	State        string
+	InstanceType string
+	PublicIP     string
+	Hostname     string
+	Address      string
+	LaunchTime   time.Time
+	Status       *mcstatus.MCStatusResponse
+	Latency      time.Duration
+}
 
-	// Setup AWS Session
-	cfg, err := getConfig()
+// GetServerInfo returns details about the instance and, if it is running, the Minecraft service
+func GetServerInfo(ctx context.Context, srv Server) (*ServerInfo, error) {
+	b, err := getBackend(ctx, srv)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Get Pixelmon EC2 instance
-	client, instance, err := getPixelmonServer(cfg)
+	// Get Pixelmon instance
+	instance, err := b.describe(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Start Pixelmon EC2 instance
-	if instance.State.Name == "running" {
-		return errors.New(Message[Online])
-	} else if instance.State.Name == "stopped" {
-		input := &ec2.StartInstancesInput{
-			InstanceIds: []string{*instance.InstanceId},
-		}
+	info := &ServerInfo{
+		State:        instance.State,
+		InstanceType: instance.Type,
+		PublicIP:     instance.PublicIP,
+		Hostname:     srv.Hostname(),
+		Address:      srv.Address(),
+		LaunchTime:   instance.LaunchTime,
+	}
+	if instance.State != instanceRunning {
+		return info, nil
+	}
 
-		_, err := client.StartInstances(context.TODO(), input)
+	// Get Minecraft service status
+	status, err := getMCStatus(ctx, srv)
+	if err != nil {
+		log.Printf("Error getting Minecraft status: %v", err)
+		return info, nil
+	}
+	info.Status = status
+
+	if status.Online {
+		latency, err := mcstatus.Ping(ctx, info.Hostname, status.Port)
 		if err != nil {
-			log.Printf("Failed to start pixelmon: %v", err)
-			return errors.New(Message[Err_Start])
+			log.Printf("Error pinging Minecraft server: %v", err)
 		}
+		info.Latency = latency
 	}
 
-	log.Println("Started Pixelmon EC2 instance")
-
-	return nil
+	return info, nil
 }
 
-// Stop turns off the EC2 instance
-func Stop() error {
-	log.Println("Stopping Pixelmon EC2 instance")
+// Start turns on the server's instance
+func Start(ctx context.Context, srv Server) (err error) {
+	log.Printf("Starting %v instance...", srv.Name)
+
+	// Let listeners know the outcome is unknown if anything fails
+	defer func() {
+		if err != nil {
+			setState(srv, StateUnknown)
+		}
+	}()
 
-	// Setup AWS Session
-	cfg, err := getConfig()
+	setState(srv, StateStarting)
+
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
 
-	// Get Pixelmon EC2 instance
-	client, instance, err := getPixelmonServer(cfg)
+	// Get Pixelmon instance
+	instance, err := b.describe(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Stop Pixelmon
-	if instance.State.Name == "stopped" {
-		return errors.New(Message[Offline])
-	} else if instance.State.Name == "running" {
-		input := &ec2.StopInstancesInput{
-			InstanceIds: []string{*instance.InstanceId},
-		}
-
-		_, err := client.StopInstances(context.TODO(), input)
-		if err != nil {
-			log.Printf("Failed to stop pixelmon: %v", err)
-			return errors.New(Message[Err_Stop])
+	// Start Pixelmon instance
+	if instance.State == instanceRunning {
+		return ErrOnline
+	} else if instance.State == instanceStopped {
+		if err := b.start(ctx); err != nil {
+			return fmt.Errorf("failed to start pixelmon: %w", err)
 		}
 	}
 
-	log.Println("Stopped Pixelmon EC2 instance")
+	log.Printf("Started %v instance", srv.Name)
 
 	return nil
 }
 
-// StartPixelmon turns on the Pixelmon Minecraft service
-func StartPixelmon() error {
-	log.Println("Starting Pixelmon service...")
+// Stop turns off the server's instance. It returns ErrOffline if the instance is already stopped, like a container or
+// process that exited with the service.
+func Stop(ctx context.Context, srv Server) (err error) {
+	log.Printf("Stopping %v instance", srv.Name)
 
-	// Wait till Pixelmon EC2 instance is running
-	for {
-		msg, err := GetStatus()
-		if err != nil {
-			return err
+	// Let listeners know the outcome is unknown if anything fails
+	defer func() {
+		if err != nil && !errors.Is(err, ErrOffline) {
+			setState(srv, StateUnknown)
 		}
+	}()
 
-		if msg == Message[Online] {
-			break
-		}
-
-		time.Sleep(delay * time.Second)
-	}
-
-	log.Println("Pixelmon EC2 instance is running")
-
-	// Check if Pixelmon service is already running
-	isOnline, _, err := mcstatus.GetMCStatus()
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
-	if isOnline {
-		log.Printf("%v.%v is online", os.Getenv("PIXELMON_SUBDOMAIN"), os.Getenv("PIXELMON_DOMAIN"))
-		return nil
-	}
 
-	cfg, err := getConfig()
+	// Get Pixelmon instance
+	instance, err := b.describe(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Create Pixelmon DNS Entry
-	_, instance, err := getPixelmonServer(cfg)
+	// Stop Pixelmon
+	if instance.State == instanceStopped {
+		setState(srv, StateOffline)
+		return ErrOffline
+	} else if instance.State == instanceRunning {
+		if err := b.stop(ctx); err != nil {
+			return fmt.Errorf("failed to stop pixelmon: %w", err)
+		}
+	}
+
+	log.Printf("Stopped %v instance", srv.Name)
+	setState(srv, StateOffline)
+
+	return nil
+}
+
+// StartServer turns on the server's instance and then the Pixelmon Minecraft service. If a stage fails after the
+// instance booted, the DNS record is deleted and the instance is stopped again so it isn't left running.
+func StartServer(ctx context.Context, srv Server) error {
+	log.Printf("Starting %v...", srv.Name)
+
+	cfg, err := getConfig(ctx, srv)
 	if err != nil {
+		setState(srv, StateUnknown)
 		return err
 	}
-	if err := createPixelmonDNSEntry(cfg, instance, os.Getenv("PIXELMON_HOSTED_ZONE_ID"), os.Getenv("PIXELMON_DOMAIN"), os.Getenv("PIXELMON_SUBDOMAIN")); err != nil {
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		setState(srv, StateUnknown)
 		return err
 	}
 
-	log.Println("Sending command to Pixelmon EC2 instance...")
+	stages := append([]stage{{
+		name: StageBootInstance,
+		run: func(ctx context.Context) error {
+			return Start(ctx, srv)
+		},
+		undo: func(ctx context.Context) error {
+			// A pending instance can't be stopped yet
+			if err := waitFor(ctx, srv, PhaseInstanceRunning, func(ctx context.Context) (bool, error) {
+				return GetStatus(ctx, srv)
+			}); err != nil {
+				log.Printf("Error waiting to stop %v instance: %v", srv.Name, err)
+			}
+
+			// A container or process that failed to start the service may have exited already
+			if err := Stop(ctx, srv); err != nil && !errors.Is(err, ErrOffline) {
+				return err
+			}
+
+			return nil
+		},
+	}}, serviceStages(cfg, b, srv)...)
+
+	return runServiceStages(ctx, srv, stages)
+}
 
-	// Send start command to Pixelmon EC2 instance
-	client := ssm.NewFromConfig(cfg)
-	documentName := "AWS-RunShellScript"
-	params := map[string][]string{
-		"commands": {"cd /opt/pixelmon/ && tmux new-session -d -s minecraft './start.sh'"},
-	}
-	input := &ssm.SendCommandInput{
-		InstanceIds:  []string{os.Getenv("PIXELMON_INSTANCE_ID")},
-		DocumentName: &documentName,
-		Parameters:   params,
+// StartPixelmon turns on the Pixelmon Minecraft service. If a stage fails, the DNS record is deleted again.
+func StartPixelmon(ctx context.Context, srv Server) error {
+	log.Printf("Starting %v service...", srv.Name)
+
+	cfg, err := getConfig(ctx, srv)
+	if err != nil {
+		setState(srv, StateUnknown)
+		return err
 	}
-	_, err = client.SendCommand(context.TODO(), input)
+	b, err := getBackend(ctx, srv)
 	if err != nil {
+		setState(srv, StateUnknown)
 		return err
 	}
 
-	log.Println("Sent command to Pixelmon EC2 instance")
+	return runServiceStages(ctx, srv, serviceStages(cfg, b, srv))
+}
 
-	// Check if Minecraft service is online
-	for {
-		isOnline, _, err := mcstatus.GetMCStatus()
-		if err != nil {
-			return err
-		}
+// serviceStages are the stages of starting the Pixelmon service once the instance is booting. cfg is used for the
+// Route53 DNS provider.
+func serviceStages(cfg aws.Config, b backend, srv Server) []stage {
+	var instance instance
+	var changeID string
+
+	return []stage{
+		{
+			name: StageWaitInstance,
+			run: func(ctx context.Context) error {
+				// Wait till Pixelmon instance is running
+				if err := waitFor(ctx, srv, PhaseInstanceRunning, func(ctx context.Context) (bool, error) {
+					return GetStatus(ctx, srv)
+				}); err != nil {
+					return err
+				}
+				log.Printf("%v instance is running", srv.Name)
+
+				return nil
+			},
+		},
+		{
+			name: StageCreateDNS,
+			run: func(ctx context.Context) error {
+				// Create Pixelmon DNS Entry
+				var err error
+				if instance, err = b.describe(ctx); err != nil {
+					return err
+				}
+				changeID, err = createPixelmonDNSEntry(ctx, cfg, srv, instance)
+
+				return err
+			},
+			undo: func(ctx context.Context) error {
+				return deletePixelmonDNSEntry(ctx, cfg, srv)
+			},
+		},
+		{
+			name: StageStartService,
+			run: func(ctx context.Context) error {
+				// Check if Pixelmon service is already running
+				status, err := getMCStatus(ctx, srv)
+				if err != nil {
+					return err
+				}
+				if status.Online {
+					log.Printf("%v is already online", srv.Hostname())
+					return nil
+				}
+
+				log.Printf("Starting %v service on instance...", srv.Name)
+
+				// Start the Minecraft service on Pixelmon instance
+				if err := b.startService(ctx); err != nil {
+					return err
+				}
+
+				log.Printf("Sent start to %v instance", srv.Name)
+
+				return nil
+			},
+		},
+		{
+			name: StageWaitDNS,
+			run: func(ctx context.Context) error {
+				// Wait for the DNS provider to serve the new record
+				return waitForDNSChange(ctx, cfg, srv, changeID)
+			},
+		},
+		{
+			name: StageVerifyDNS,
+			run: func(ctx context.Context) error {
+				// Wait for the hostname to resolve to the instance so players can connect once it is online
+				if err := verifyDNS(ctx, srv, instance.address()); err != nil {
+					return err
+				}
+				log.Printf("%v resolves to %v", srv.Hostname(), instance.address())
+
+				return nil
+			},
+		},
+		{
+			name: StageWaitService,
+			run: func(ctx context.Context) error {
+				// Check if Minecraft service is online
+				if err := waitFor(ctx, srv, PhaseServiceOnline, serviceOnline(srv)); err != nil {
+					return err
+				}
+				log.Printf("%v is online", srv.Hostname())
+
+				return nil
+			},
+		},
+	}
+}
+
+// runServiceStages runs the stages of starting the server and lets listeners know how it went
+func runServiceStages(ctx context.Context, srv Server, stages []stage) error {
+	setState(srv, StateStarting)
 
-		if isOnline {
-			log.Printf("%v.%v is online", os.Getenv("PIXELMON_SUBDOMAIN"), os.Getenv("PIXELMON_DOMAIN"))
-			break
+	err := runStages(ctx, srv, stages)
+	if err != nil {
+		// The server is known to be offline only if the instance was stopped again
+		var seqErr *SequenceError
+		if errors.As(err, &seqErr) && slices.Contains(seqErr.Undone, StageBootInstance) {
+			setState(srv, StateOffline)
+		} else {
+			setState(srv, StateUnknown)
 		}
 
-		log.Println("Waiting for Pixelmon service to start...")
-		time.Sleep(delay * time.Second)
+		return err
 	}
 
-	log.Println("Started Pixelmon service")
+	log.Printf("Started %v service", srv.Name)
+	setState(srv, StateOnline)
 
 	return nil
 }
 
 // StopPixelmon turns off the Pixelmon Minecraft service
-func StopPixelmon() error {
-	log.Println("Stopping Pixelmon service")
+func StopPixelmon(ctx context.Context, srv Server) (err error) {
+	log.Printf("Stopping %v service", srv.Name)
 
-	// Wait till Pixelmon EC2 instance is running
-	for {
-		msg, err := GetStatus()
+	// Let listeners know the outcome is unknown if anything fails
+	defer func() {
 		if err != nil {
-			return err
+			setState(srv, StateUnknown)
 		}
+	}()
 
-		if msg == Message[Online] {
-			break
-		}
+	setState(srv, StateStopping)
 
-		time.Sleep(delay * time.Second)
+	// Wait till Pixelmon instance is running
+	if err := waitFor(ctx, srv, PhaseInstanceRunning, func(ctx context.Context) (bool, error) {
+		return GetStatus(ctx, srv)
+	}); err != nil {
+		return err
 	}
 
-	log.Println("Pixelmon EC2 instance is running")
+	log.Printf("%v instance is running", srv.Name)
 
-	cfg, err := getConfig()
+	cfg, err := getConfig(ctx, srv)
+	if err != nil {
+		return err
+	}
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
 
 	// Delete Pixelmon DNS Entry
-	_, instance, err := getPixelmonServer(cfg)
-	if err != nil {
+	if err := deletePixelmonDNSEntry(ctx, cfg, srv); err != nil {
+		return err
+	}
+
+	// A hibernating instance keeps the service running, so only flush the world to disk
+	if srv.Hibernate {
+		instance, err := b.describe(ctx)
+		if err != nil {
+			return err
+		}
+		if instance.Hibernation {
+			if err := b.console(ctx, "save-all flush"); err != nil {
+				return err
+			}
+			log.Printf("%v saved the world before hibernating", srv.Name)
+
+			return sleep(ctx, delay*time.Second)
+		}
+	}
+
+	// Send stop command to Pixelmon instance
+	if err := b.console(ctx, "stop"); err != nil {
 		return err
 	}
-	if err := deletePixelmonDNSEntry(cfg, instance, os.Getenv("PIXELMON_HOSTED_ZONE_ID"), os.Getenv("PIXELMON_DOMAIN"), os.Getenv("PIXELMON_SUBDOMAIN")); err != nil {
+
+	// Checks if Minecraft service is offline
+	if err := waitFor(ctx, srv, PhaseServiceOffline, serviceOffline(srv)); err != nil {
 		return err
 	}
+	log.Printf("%v is offline", srv.Hostname())
 
-	// Send start command to Pixelmon EC2 instance
-	client := ssm.NewFromConfig(cfg)
-	documentName := "AWS-RunShellScript"
-	params := map[string][]string{
-		"commands": {"mcrcon -H localhost -p " + os.Getenv("RCON_PASSWORD") + " \"stop\""},
+	// Give the service time to save the world before the instance is stopped
+	return sleep(ctx, delay*time.Second)
+}
+
+// RestartPixelmon restarts the Pixelmon Minecraft service without stopping the instance
+func RestartPixelmon(ctx context.Context, srv Server) (err error) {
+	log.Printf("Restarting %v service...", srv.Name)
+
+	// Let listeners know the outcome is unknown if anything fails
+	defer func() {
+		if err != nil {
+			setState(srv, StateUnknown)
+		}
+	}()
+
+	setState(srv, StateRestarting)
+
+	// Check if Pixelmon service is running
+	status, err := getMCStatus(ctx, srv)
+	if err != nil {
+		return err
 	}
-	input := &ssm.SendCommandInput{
-		InstanceIds:  []string{os.Getenv("PIXELMON_INSTANCE_ID")},
-		DocumentName: &documentName,
-		Parameters:   params,
+	if !status.Online {
+		return ErrOffline
 	}
-	_, err = client.SendCommand(context.TODO(), input)
+
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
 
-	// Checks if Minecraft service is offline
-	for {
-		isOnline, _, err := mcstatus.GetMCStatus()
-		if err != nil {
+	// Warn players before restarting
+	messages := catalog.Default()
+	for _, warning := range restartWarnings {
+		text := messages.Get(catalog.DefaultLocale, "ingame.restarting", map[string]any{"Time": messages.Duration(catalog.DefaultLocale, warning.remaining)})
+		if err := b.console(ctx, "say "+text); err != nil {
 			return err
 		}
-
-		if !isOnline {
-			log.Printf("%v.%v is offline", os.Getenv("PIXELMON_SUBDOMAIN"), os.Getenv("PIXELMON_DOMAIN"))
-			time.Sleep(delay * time.Second)
-			break
+		if err := sleep(ctx, warning.wait); err != nil {
+			return err
 		}
+	}
 
-		log.Println("Waiting for Pixelmon service to stop...")
-		time.Sleep(delay * time.Second)
+	// Save the world and stop the Pixelmon service
+	if err := b.console(ctx, "save-all", "stop"); err != nil {
+		return err
 	}
 
+	// Wait till Pixelmon service is offline
+	if err := waitFor(ctx, srv, PhaseServiceOffline, serviceOffline(srv)); err != nil {
+		return err
+	}
+	log.Printf("%v is offline", srv.Hostname())
+
+	// Start the Minecraft service on Pixelmon instance once the old one has exited
+	if err := b.restartService(ctx); err != nil {
+		return err
+	}
+
+	// Check if Minecraft service is online
+	if err := waitFor(ctx, srv, PhaseServiceOnline, serviceOnline(srv)); err != nil {
+		return err
+	}
+	log.Printf("%v is online", srv.Hostname())
+
+	log.Printf("Restarted %v service", srv.Name)
+	setState(srv, StateOnline)
+
 	return nil
 }
 
 // AddToWhitelist takes a username and runs the /whitelist add command
-func AddToWhitelist(username string) error {
-	cfg, err := getConfig()
+func AddToWhitelist(ctx context.Context, srv Server, username string) error {
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
 
-	// Send start command to Pixelmon EC2 instance
-	client := ssm.NewFromConfig(cfg)
-	documentName := "AWS-RunShellScript"
-	params := map[string][]string{
-		"commands": {"mcrcon -H localhost -p " + os.Getenv("RCON_PASSWORD") + " \"whitelist add " + username + "\""},
+	// Send whitelist command to Pixelmon instance
+	if err := b.console(ctx, "whitelist add "+username); err != nil {
+		return err
 	}
-	input := &ssm.SendCommandInput{
-		InstanceIds:  []string{os.Getenv("PIXELMON_INSTANCE_ID")},
-		DocumentName: &documentName,
-		Parameters:   params,
+
+	return nil
+}
+
+// GetWhitelist gets the names of the players on the server's whitelist
+func GetWhitelist(ctx context.Context, srv Server) ([]string, error) {
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		return nil, err
 	}
-	_, err = client.SendCommand(context.TODO(), input)
+
+	// Read the whitelist file on Pixelmon instance
+	output, err := b.readFile(ctx, whitelistFile)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	var entries []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return nil, fmt.Errorf("invalid whitelist: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+
+	return names, nil
+}
+
+// GetServiceStatus checks the Minecraft service of the server, retrying like the other status calls
+func GetServiceStatus(ctx context.Context, srv Server) (*mcstatus.MCStatusResponse, error) {
+	return getMCStatus(ctx, srv)
+}
+
+// GetPlayerNames returns the names of the players currently online on the Minecraft server
+func GetPlayerNames(ctx context.Context, srv Server) ([]string, error) {
+	return callValue(ctx, srv, StepCheckStatus, func(ctx context.Context) ([]string, error) {
+		return mcstatus.GetPlayerNames(ctx, srv.Address())
+	})
 }
 
 // GetNumberOfPlayers gets the number of online players on the Minecraft server
-func GetNumberOfPlayers() (int, error) {
-	_, num, err := mcstatus.GetMCStatus()
+func GetNumberOfPlayers(ctx context.Context, srv Server) (int, error) {
+	status, err := getMCStatus(ctx, srv)
 	if err != nil {
 		return 0, err
 	}
 
-	return num, nil
+	return status.Players.Online, nil
 }
 
 // SendMessage takes a message and runs the /say command
-func SendMessage(msg string) error {
-	cfg, err := getConfig()
+func SendMessage(ctx context.Context, srv Server, msg string) error {
+	b, err := getBackend(ctx, srv)
 	if err != nil {
 		return err
 	}
 
-	// Send start command to Pixelmon EC2 instance
-	client := ssm.NewFromConfig(cfg)
-	documentName := "AWS-RunShellScript"
-	params := map[string][]string{
-		"commands": {"mcrcon -H localhost -p " + os.Getenv("RCON_PASSWORD") + " \"say " + msg + "\""},
-	}
-	input := &ssm.SendCommandInput{
-		InstanceIds:  []string{os.Getenv("PIXELMON_INSTANCE_ID")},
-		DocumentName: &documentName,
-		Parameters:   params,
-	}
-	_, err = client.SendCommand(context.TODO(), input)
-	if err != nil {
+	// Send say command to Pixelmon instance
+	if err := b.console(ctx, "say "+msg); err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// serviceOnline returns a check for waitFor that the Minecraft service is online
+func serviceOnline(srv Server) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		status, err := getMCStatus(ctx, srv)
+		if err != nil {
+			return false, err
+		}
+
+		return status.Online, nil
+	}
+}
+
+// serviceOffline returns a check for waitFor that the Minecraft service is offline
+func serviceOffline(srv Server) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		status, err := getMCStatus(ctx, srv)
+		if err != nil {
+			return false, err
+		}
+
+		return !status.Online, nil
+	}
+}
+
+// getMCStatus checks the Minecraft service of the server with mcstatus.io
+func getMCStatus(ctx context.Context, srv Server) (*mcstatus.MCStatusResponse, error) {
+	return callValue(ctx, srv, StepCheckStatus, func(ctx context.Context) (*mcstatus.MCStatusResponse, error) {
+		return mcstatus.GetMCStatusResponse(ctx, srv.Address())
+	})
+}
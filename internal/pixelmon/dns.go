@@ -0,0 +1,230 @@
+package pixelmon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kn-lim/seigetsu-bot/internal/dns"
+)
+
+// resolverTimeout limits each lookup of the server's hostname
+const resolverTimeout = 5 * time.Second
+
+// Statuses of DNS changes
+const (
+	dnsPending = "PENDING"
+	dnsInSync  = "INSYNC"
+)
+
+// DNSStatus is the state of the server's DNS record while waiting for it to be usable
+type DNSStatus struct {
+	// Change is the status of the change, PENDING or INSYNC
+	Change string
+
+	// Addresses are what the hostname resolves to. IP is the address it should resolve to.
+	Addresses []string
+	IP        string
+}
+
+// resolver looks up the server's hostname. It uses the DNS server in PIXELMON_DNS_RESOLVER, like "1.1.1.1:53",
+// falling back to the system's resolver. A public resolver sees the record like players do.
+var resolver = newResolver(os.Getenv("PIXELMON_DNS_RESOLVER"))
+
+// newResolver returns a resolver that asks the DNS server at address, or the system's resolver if address is empty
+func newResolver(address string) *net.Resolver {
+	if address == "" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			dialer := net.Dialer{Timeout: resolverTimeout}
+			return dialer.DialContext(ctx, network, address)
+		},
+	}
+}
+
+// Providers that don't depend on the server are shared, so the hosts file is only changed by one of them at a time.
+// The hosts file is at PIXELMON_HOSTS_FILE.
+var (
+	cloudflareProvider = dns.NewCloudflare()
+	hostsProvider      = dns.NewHosts(getEnv("PIXELMON_HOSTS_FILE", "/etc/hosts"))
+)
+
+// getDNSProvider returns the provider that manages the server's DNS records
+func getDNSProvider(cfg aws.Config, srv Server) (dns.Provider, error) {
+	switch srv.DNSProvider {
+	case "", dns.ProviderRoute53:
+		return dns.NewRoute53(cfg), nil
+	case dns.ProviderCloudflare:
+		return cloudflareProvider, nil
+	case dns.ProviderHosts:
+		return hostsProvider, nil
+	}
+
+	return nil, fmt.Errorf("unknown DNS provider %q", srv.DNSProvider)
+}
+
+// dnsRecords returns the records that point the server at the instance: A and AAAA records for the addresses it has
+// and, if enabled, an SRV record with the port
+func dnsRecords(srv Server, instance instance) []dns.Record {
+	ttl := srv.RecordTTL()
+
+	var records []dns.Record
+	if instance.PublicIP != "" {
+		records = append(records, dns.Record{Name: srv.Hostname(), Type: dns.TypeA, Value: instance.PublicIP, TTL: ttl})
+	}
+	if instance.IPv6 != "" {
+		records = append(records, dns.Record{Name: srv.Hostname(), Type: dns.TypeAAAA, Value: instance.IPv6, TTL: ttl})
+	}
+	if srv.SRV {
+		// Priority, weight, port and target
+		value := fmt.Sprintf("0 5 %d %s", srv.MinecraftPort(), srv.Hostname())
+		records = append(records, dns.Record{Name: srv.SRVName(), Type: dns.TypeSRV, Value: value, TTL: ttl})
+	}
+
+	return records
+}
+
+// managedRecords returns the names and types of every record the bot manages for the server
+func managedRecords(srv Server) []dns.Record {
+	return []dns.Record{
+		{Name: srv.Hostname(), Type: dns.TypeA},
+		{Name: srv.Hostname(), Type: dns.TypeAAAA},
+		{Name: srv.SRVName(), Type: dns.TypeSRV},
+	}
+}
+
+// skipsDNS is whether the bot manages no DNS records for the server, which is a docker or process server without a
+// public_ip that players reach some other way
+func skipsDNS(srv Server) bool {
+	return (srv.Backend == BackendDocker || srv.Backend == BackendProcess) && srv.PublicIP == ""
+}
+
+// createPixelmonDNSEntry points the server's records at the instance and returns the ID of the change
+func createPixelmonDNSEntry(ctx context.Context, cfg aws.Config, srv Server, instance instance) (string, error) {
+	if skipsDNS(srv) {
+		log.Printf("%v has no public IP, so it has no DNS records", srv.Name)
+		return "", nil
+	}
+
+	provider, err := getDNSProvider(cfg, srv)
+	if err != nil {
+		return "", err
+	}
+
+	records := dnsRecords(srv, instance)
+	if len(records) == 0 {
+		return "", fmt.Errorf("%v instance has no public address", srv.Name)
+	}
+	for _, record := range records {
+		if err := record.Validate(); err != nil {
+			return "", err
+		}
+		log.Printf("Upserting %v record of %v to %v", record.Type, record.Name, record.Value)
+	}
+
+	changeID, err := callValue(ctx, srv, StepUpdateDNS, func(ctx context.Context) (string, error) {
+		return provider.Upsert(ctx, srv.HostedZoneID, records)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create DNS records: %w", err)
+	}
+
+	log.Printf("Created DNS records of %v", srv.Hostname())
+
+	return changeID, nil
+}
+
+// deletePixelmonDNSEntry deletes every record the bot manages for the server, whatever they point at, so records
+// from an older IP address or TTL are cleaned up too
+func deletePixelmonDNSEntry(ctx context.Context, cfg aws.Config, srv Server) error {
+	if skipsDNS(srv) {
+		return nil
+	}
+
+	provider, err := getDNSProvider(cfg, srv)
+	if err != nil {
+		return err
+	}
+
+	err = call(ctx, srv, StepUpdateDNS, func(ctx context.Context) error {
+		return provider.Delete(ctx, srv.HostedZoneID, managedRecords(srv))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete DNS records: %w", err)
+	}
+
+	log.Printf("Deleted DNS records of %v", srv.Hostname())
+
+	return nil
+}
+
+// waitForDNSChange waits for the provider to apply the change to all of its name servers
+func waitForDNSChange(ctx context.Context, cfg aws.Config, srv Server, changeID string) error {
+	if skipsDNS(srv) {
+		return nil
+	}
+
+	provider, err := getDNSProvider(cfg, srv)
+	if err != nil {
+		return err
+	}
+
+	return waitFor(ctx, srv, PhaseDNSInSync, func(ctx context.Context) (bool, error) {
+		synced, err := callValue(ctx, srv, StepCheckDNS, func(ctx context.Context) (bool, error) {
+			return provider.Synced(ctx, changeID)
+		})
+		if err != nil {
+			return false, err
+		}
+
+		status := dnsPending
+		if synced {
+			status = dnsInSync
+		}
+		reportProgress(ctx, Progress{Server: srv, Stage: StageWaitDNS, DNS: &DNSStatus{Change: status}})
+
+		return synced, nil
+	})
+}
+
+// verifyDNS waits for the server's hostname to resolve to the IPv4 or IPv6 address
+func verifyDNS(ctx context.Context, srv Server, ip string) error {
+	if skipsDNS(srv) {
+		return nil
+	}
+
+	want := net.ParseIP(ip)
+
+	return waitFor(ctx, srv, PhaseDNSResolved, func(ctx context.Context) (bool, error) {
+		lookupCtx, cancel := context.WithTimeout(ctx, resolverTimeout)
+		defer cancel()
+
+		// Not resolving yet is expected while caches expire, so lookup errors only mean waiting longer
+		addresses, err := resolver.LookupHost(lookupCtx, srv.Hostname())
+		if err != nil {
+			log.Printf("Error resolving %v: %v", srv.Hostname(), err)
+		}
+		reportProgress(ctx, Progress{Server: srv, Stage: StageVerifyDNS, DNS: &DNSStatus{
+			Change:    dnsInSync,
+			Addresses: addresses,
+			IP:        ip,
+		}})
+
+		// IPv6 addresses can be written in several ways, so they are compared parsed
+		return slices.ContainsFunc(addresses, func(address string) bool {
+			return net.ParseIP(address).Equal(want)
+		}), nil
+	})
+}
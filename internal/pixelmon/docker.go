@@ -0,0 +1,158 @@
+package pixelmon
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// dockerConsole is the command in the container that runs a Minecraft command, set by PIXELMON_DOCKER_CONSOLE
+	dockerConsole = getEnv("PIXELMON_DOCKER_CONSOLE", "rcon-cli")
+
+	// dockerDataDir is the server's directory in the container, set by PIXELMON_DOCKER_DATA
+	dockerDataDir = getEnv("PIXELMON_DOCKER_DATA", "/data")
+)
+
+// dockerBackend runs the server in a Docker container, which is the instance ID. The container runs the Minecraft
+// service, so it stops when the service does.
+type dockerBackend struct {
+	srv Server
+}
+
+// docker runs the docker command and returns its output
+func (b dockerBackend) docker(ctx context.Context, step Step, args ...string) (string, error) {
+	return callValue(ctx, b.srv, step, func(ctx context.Context) (string, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "docker", args...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if err := cmd.Run(); err != nil {
+			message := strings.TrimSpace(stderr.String())
+			if strings.Contains(message, "No such") {
+				return "", ErrNotFound
+			}
+			return "", fmt.Errorf("docker %v: %w: %v", args[0], err, message)
+		}
+
+		return stdout.String(), nil
+	})
+}
+
+func (b dockerBackend) describe(ctx context.Context) (instance, error) {
+	output, err := b.docker(ctx, StepDescribeInstance, "inspect", "--type", "container",
+		"--format", "{{.Id}}\t{{.State.Status}}\t{{.State.StartedAt}}\t{{.Config.Image}}", b.srv.InstanceID)
+	if err != nil {
+		return instance{}, err
+	}
+
+	fields := strings.Split(strings.TrimSpace(output), "\t")
+	if len(fields) != 4 {
+		return instance{}, fmt.Errorf("invalid docker inspect output %q", output)
+	}
+
+	result := instance{
+		ID:       fields[0],
+		Type:     fields[3],
+		PublicIP: b.srv.PublicIP,
+	}
+	switch fields[1] {
+	case "running":
+		result.State = instanceRunning
+	case "restarting":
+		result.State = instancePending
+	case "removing":
+		result.State = instanceStopping
+	case "paused":
+		// A paused container's service can't answer, so it is started by unpausing it
+		result.State = instanceStopped
+		result.Paused = true
+	default:
+		result.State = instanceStopped
+	}
+	if launchTime, err := time.Parse(time.RFC3339Nano, fields[2]); err == nil && result.State == instanceRunning {
+		result.LaunchTime = launchTime
+	}
+
+	return result, nil
+}
+
+func (b dockerBackend) start(ctx context.Context) error {
+	// docker start does nothing for a paused container, which is still running
+	current, err := b.describe(ctx)
+	if err != nil {
+		return err
+	}
+	command := "start"
+	if current.Paused {
+		command = "unpause"
+	}
+
+	_, err = b.docker(ctx, StepStartInstance, command, b.srv.InstanceID)
+	return err
+}
+
+func (b dockerBackend) stop(ctx context.Context) error {
+	timeout := strconv.Itoa(int(localStopTimeout.Seconds()))
+	_, err := b.docker(ctx, StepStopInstance, "stop", "--time", timeout, b.srv.InstanceID)
+	return err
+}
+
+func (b dockerBackend) startService(ctx context.Context) error {
+	// Starting the container starts the service, and does nothing if it is running
+	return b.start(ctx)
+}
+
+func (b dockerBackend) restartService(ctx context.Context) error {
+	// Wait for the container to exit once the service stopped
+	if _, err := b.docker(ctx, StepSendCommand, "wait", b.srv.InstanceID); err != nil {
+		return err
+	}
+
+	return b.start(ctx)
+}
+
+func (b dockerBackend) console(ctx context.Context, commands ...string) error {
+	for _, command := range commands {
+		args := append([]string{"exec", b.srv.InstanceID}, strings.Fields(dockerConsole)...)
+		if _, err := b.docker(ctx, StepSendCommand, append(args, command)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b dockerBackend) query(ctx context.Context, command string) (string, error) {
+	args := append([]string{"exec", b.srv.InstanceID}, strings.Fields(dockerConsole)...)
+	return b.docker(ctx, StepCommandOutput, append(args, command)...)
+}
+
+func (b dockerBackend) readFile(ctx context.Context, name string) (string, error) {
+	return b.docker(ctx, StepCommandOutput, "exec", b.srv.InstanceID, "cat", path.Join(dockerDataDir, name))
+}
+
+func (b dockerBackend) writeFile(ctx context.Context, name string, contents string) error {
+	_, err := b.docker(ctx, StepSendCommand, "exec", b.srv.InstanceID, "sh", "-c", writeFileCommand(path.Join(dockerDataDir, name), contents))
+	return err
+}
+
+func (b dockerBackend) shell(ctx context.Context, script string) (string, error) {
+	return b.docker(ctx, StepCommandOutput, "exec", "--workdir", dockerDataDir, b.srv.InstanceID, "sh", "-c", script)
+}
+
+// getEnv reads the environment variable, falling back to def if it isn't set
+func getEnv(key string, def string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return def
+}
@@ -0,0 +1,234 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+var ErrMultipleInstances = errors.New("several instances match")
+
+// hibernatedReason is the state reason of an EC2 instance that was stopped by hibernating it
+const hibernatedReason = "Client.UserInitiatedHibernate"
+
+// instanceIDs are the IDs of the instances found by their Name tag, keyed by server key
+var (
+	instanceIDs   = make(map[string]string)
+	instanceIDsMu sync.Mutex
+)
+
+// ec2Backend runs the server on an EC2 instance, running commands on it through SSM
+type ec2Backend struct {
+	cfg    aws.Config
+	srv    Server
+	client *ec2.Client
+
+	// hibernation is whether the instance can hibernate, as of the last time it was described
+	hibernation bool
+}
+
+func newEC2Backend(cfg aws.Config, srv Server) *ec2Backend {
+	// The calls are retried by call, so the SDK doesn't retry them as well
+	client := ec2.NewFromConfig(cfg, func(o *ec2.Options) {
+		o.RetryMaxAttempts = 1
+	})
+
+	return &ec2Backend{
+		cfg:    cfg,
+		srv:    srv,
+		client: client,
+	}
+}
+
+// resolveInstanceID sets the ID of the server's instance, finding it by its Name tag if the server has one
+func (b *ec2Backend) resolveInstanceID(ctx context.Context) error {
+	if b.srv.NameTag == "" {
+		return nil
+	}
+
+	instanceIDsMu.Lock()
+	id, ok := instanceIDs[b.srv.Key()]
+	instanceIDsMu.Unlock()
+	if !ok {
+		var err error
+		if id, err = findInstance(ctx, b.client, b.srv); err != nil {
+			return err
+		}
+
+		instanceIDsMu.Lock()
+		instanceIDs[b.srv.Key()] = id
+		instanceIDsMu.Unlock()
+	}
+	b.srv.InstanceID = id
+
+	return nil
+}
+
+func (b *ec2Backend) describe(ctx context.Context) (instance, error) {
+	ec2Instance, err := getPixelmonServer(ctx, b.client, b.srv)
+
+	// The instance found by the Name tag was replaced, like when it is rebuilt from an AMI, so find it again
+	if b.srv.NameTag != "" && (isInstanceGone(err) || err == nil && ec2Instance.State.Name == ec2Types.InstanceStateNameTerminated) {
+		log.Printf("%v EC2 instance %v is gone, finding it by its Name tag again", b.srv.Name, b.srv.InstanceID)
+
+		instanceIDsMu.Lock()
+		delete(instanceIDs, b.srv.Key())
+		instanceIDsMu.Unlock()
+
+		if err := b.resolveInstanceID(ctx); err != nil {
+			return instance{}, err
+		}
+		ec2Instance, err = getPixelmonServer(ctx, b.client, b.srv)
+	}
+	if err != nil {
+		return instance{}, err
+	}
+
+	result := instance{
+		ID:       aws.ToString(ec2Instance.InstanceId),
+		State:    string(ec2Instance.State.Name),
+		Type:     string(ec2Instance.InstanceType),
+		PublicIP: aws.ToString(ec2Instance.PublicIpAddress),
+		IPv6:     aws.ToString(ec2Instance.Ipv6Address),
+		Spot:     ec2Instance.InstanceLifecycle == ec2Types.InstanceLifecycleTypeSpot,
+	}
+	if ec2Instance.LaunchTime != nil {
+		result.LaunchTime = *ec2Instance.LaunchTime
+	}
+	if ec2Instance.HibernationOptions != nil {
+		result.Hibernation = aws.ToBool(ec2Instance.HibernationOptions.Configured)
+	}
+	b.hibernation = result.Hibernation
+	if result.State == instanceStopped && ec2Instance.StateReason != nil {
+		result.Hibernated = aws.ToString(ec2Instance.StateReason.Code) == hibernatedReason
+	}
+
+	return result, nil
+}
+
+func (b *ec2Backend) start(ctx context.Context) error {
+	input := &ec2.StartInstancesInput{
+		InstanceIds: []string{b.srv.InstanceID},
+	}
+
+	return call(ctx, b.srv, StepStartInstance, func(ctx context.Context) error {
+		_, err := b.client.StartInstances(ctx, input)
+		return err
+	})
+}
+
+func (b *ec2Backend) stop(ctx context.Context) error {
+	if b.srv.Hibernate && !b.hibernation {
+		log.Printf("%v EC2 instance isn't configured for hibernation, stopping it instead", b.srv.Name)
+	}
+
+	input := &ec2.StopInstancesInput{
+		InstanceIds: []string{b.srv.InstanceID},
+		Hibernate:   aws.Bool(b.srv.Hibernate && b.hibernation),
+	}
+
+	return call(ctx, b.srv, StepStopInstance, func(ctx context.Context) error {
+		_, err := b.client.StopInstances(ctx, input)
+		return err
+	})
+}
+
+// modifyInstanceType changes the type of the stopped instance
+func (b *ec2Backend) modifyInstanceType(ctx context.Context, instanceType string) error {
+	_, err := b.client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(b.srv.InstanceID),
+		InstanceType: &ec2Types.AttributeValue{Value: aws.String(instanceType)},
+	})
+
+	return err
+}
+
+func (b *ec2Backend) startService(ctx context.Context) error {
+	// An instance resumed from hibernation still has the service's tmux session
+	return sendCommand(ctx, b.cfg, b.srv, "tmux has-session -t minecraft 2>/dev/null || "+startCommand)
+}
+
+func (b *ec2Backend) restartService(ctx context.Context) error {
+	// Wait for the old tmux session to exit first
+	return sendCommand(ctx, b.cfg, b.srv, "while tmux has-session -t minecraft 2>/dev/null; do sleep 1; done", startCommand)
+}
+
+func (b *ec2Backend) console(ctx context.Context, commands ...string) error {
+	shell := make([]string, 0, len(commands))
+	for _, command := range commands {
+		shell = append(shell, rcon(command))
+	}
+
+	return sendCommand(ctx, b.cfg, b.srv, shell...)
+}
+
+func (b *ec2Backend) query(ctx context.Context, command string) (string, error) {
+	return runCommand(ctx, b.cfg, b.srv, rcon(command))
+}
+
+func (b *ec2Backend) readFile(ctx context.Context, name string) (string, error) {
+	return runCommand(ctx, b.cfg, b.srv, "cat "+serverDir+"/"+strings.TrimPrefix(name, "/"))
+}
+
+func (b *ec2Backend) writeFile(ctx context.Context, name string, contents string) error {
+	_, err := runCommand(ctx, b.cfg, b.srv, writeFileCommand(serverDir+"/"+strings.TrimPrefix(name, "/"), contents))
+	return err
+}
+
+func (b *ec2Backend) shell(ctx context.Context, script string) (string, error) {
+	return runCommand(ctx, b.cfg, b.srv, "cd "+serverDir, script)
+}
+
+// findInstance finds the ID of the only instance with the server's Name tag that isn't terminated
+func findInstance(ctx context.Context, client *ec2.Client, srv Server) (string, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []ec2Types.Filter{
+			{Name: aws.String("tag:Name"), Values: []string{srv.NameTag}},
+			{Name: aws.String("instance-state-name"), Values: []string{"pending", "running", "stopping", "stopped"}},
+		},
+	}
+
+	var ids []string
+	paginator := ec2.NewDescribeInstancesPaginator(client, input)
+	for paginator.HasMorePages() {
+		result, err := callValue(ctx, srv, StepDescribeInstance, func(ctx context.Context) (*ec2.DescribeInstancesOutput, error) {
+			return paginator.NextPage(ctx)
+		})
+		if err != nil {
+			return "", fmt.Errorf("error finding instance: %w", err)
+		}
+
+		for _, reservation := range result.Reservations {
+			for _, instance := range reservation.Instances {
+				ids = append(ids, aws.ToString(instance.InstanceId))
+			}
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w with Name tag %q", ErrNotFound, srv.NameTag)
+	case 1:
+		log.Printf("Found %v EC2 instance %v by Name tag %q", srv.Name, ids[0], srv.NameTag)
+		return ids[0], nil
+	}
+
+	return "", fmt.Errorf("%w Name tag %q: %v", ErrMultipleInstances, srv.NameTag, strings.Join(ids, ", "))
+}
+
+// isInstanceGone checks if describing the instance failed because it doesn't exist anymore
+func isInstanceGone(err error) bool {
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+
+	var apiErr interface{ ErrorCode() string }
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidInstanceID.NotFound"
+}
@@ -0,0 +1,138 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var ErrUnknownGameRule = errors.New("unknown game rule")
+
+// formatCodes are the color and style codes in Minecraft responses
+var formatCodes = regexp.MustCompile("§.")
+
+// gameRules are the game rules that can be changed and the values they accept
+var gameRules = map[string]Schema{
+	"announceAdvancements":       boolSchema(),
+	"commandBlockOutput":         boolSchema(),
+	"disableElytraMovementCheck": boolSchema(),
+	"disableRaids":               boolSchema(),
+	"doDaylightCycle":            boolSchema(),
+	"doEntityDrops":              boolSchema(),
+	"doFireTick":                 boolSchema(),
+	"doImmediateRespawn":         boolSchema(),
+	"doInsomnia":                 boolSchema(),
+	"doLimitedCrafting":          boolSchema(),
+	"doMobLoot":                  boolSchema(),
+	"doMobSpawning":              boolSchema(),
+	"doPatrolSpawning":           boolSchema(),
+	"doTileDrops":                boolSchema(),
+	"doTraderSpawning":           boolSchema(),
+	"doWeatherCycle":             boolSchema(),
+	"drowningDamage":             boolSchema(),
+	"fallDamage":                 boolSchema(),
+	"fireDamage":                 boolSchema(),
+	"forgiveDeadPlayers":         boolSchema(),
+	"keepInventory":              boolSchema(),
+	"logAdminCommands":           boolSchema(),
+	"maxCommandChainLength":      intSchema(0, math.MaxInt32),
+	"maxEntityCramming":          intSchema(0, math.MaxInt32),
+	"mobGriefing":                boolSchema(),
+	"naturalRegeneration":        boolSchema(),
+	"randomTickSpeed":            intSchema(0, math.MaxInt32),
+	"reducedDebugInfo":           boolSchema(),
+	"sendCommandFeedback":        boolSchema(),
+	"showDeathMessages":          boolSchema(),
+	"spawnRadius":                intSchema(0, math.MaxInt32),
+	"spectatorsGenerateChunks":   boolSchema(),
+	"universalAnger":             boolSchema(),
+}
+
+// GameRuleNames returns the game rules that can be changed, in order
+func GameRuleNames() []string {
+	names := make([]string, 0, len(gameRules))
+	for name := range gameRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LookupGameRule returns the schema of the game rule
+func LookupGameRule(rule string) (Schema, bool) {
+	schema, ok := gameRules[rule]
+	return schema, ok
+}
+
+// GetGameRule returns the value of the game rule on the running service
+func GetGameRule(ctx context.Context, srv Server, rule string) (string, error) {
+	if _, ok := gameRules[rule]; !ok {
+		return "", ErrUnknownGameRule
+	}
+
+	b, err := onlineBackend(ctx, srv)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := b.query(ctx, "gamerule "+rule)
+	if err != nil {
+		return "", err
+	}
+
+	return parseGameRule(response)
+}
+
+// parseGameRule returns the value in the response to the gamerule command, which is like "Gamerule keepInventory is
+// currently set to: false"
+func parseGameRule(response string) (string, error) {
+	response = strings.TrimSpace(formatCodes.ReplaceAllString(response, ""))
+	index := strings.LastIndex(response, ": ")
+	if index < 0 {
+		return "", fmt.Errorf("unexpected gamerule response %q", response)
+	}
+
+	return response[index+2:], nil
+}
+
+// SetGameRule changes the game rule on the running service, which applies it right away and saves it with the world
+func SetGameRule(ctx context.Context, srv Server, rule string, value string) error {
+	schema, ok := gameRules[rule]
+	if !ok {
+		return ErrUnknownGameRule
+	}
+	if err := schema.Validate(value); err != nil {
+		return err
+	}
+
+	b, err := onlineBackend(ctx, srv)
+	if err != nil {
+		return err
+	}
+
+	if err := b.console(ctx, "gamerule "+rule+" "+value); err != nil {
+		return err
+	}
+	log.Printf("Set game rule %v to %v on %v", rule, value, srv.Name)
+
+	return nil
+}
+
+// onlineBackend returns the server's backend, or ErrOffline if the Minecraft service isn't online
+func onlineBackend(ctx context.Context, srv Server) (backend, error) {
+	status, err := getMCStatus(ctx, srv)
+	if err != nil {
+		return nil, err
+	}
+	if !status.Online {
+		return nil, ErrOffline
+	}
+
+	return getBackend(ctx, srv)
+}
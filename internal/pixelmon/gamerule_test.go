@@ -0,0 +1,27 @@
+package pixelmon
+
+import "testing"
+
+func TestParseGameRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     string
+		wantErr  bool
+	}{
+		{name: "bool", response: "Gamerule keepInventory is currently set to: false", want: "false"},
+		{name: "int", response: "Gamerule randomTickSpeed is currently set to: 3\n", want: "3"},
+		{name: "format codes", response: "§7Gamerule doFireTick is currently set to: §atrue§r", want: "true"},
+		{name: "unknown rule", response: "Incorrect argument for command", wantErr: true},
+		{name: "empty", response: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGameRule(tt.response)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseGameRule(%q) = %q, %v, want %q, wantErr %v", tt.response, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
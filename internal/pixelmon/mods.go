@@ -0,0 +1,195 @@
+package pixelmon
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// modsScript prints the Forge install and, for each jar in mods, its name and the parts of its metadata that describe
+// its mods. Only those lines are printed since the output of commands on EC2 is cut off after 24,000 characters.
+const modsScript = `ls -d libraries/net/minecraftforge/forge/*/ forge-*.jar 2>/dev/null | sed 's/^/forge: /'
+for f in mods/*.jar; do
+  [ -e "$f" ] || continue
+  echo "jar: ${f#mods/}"
+  unzip -p "$f" META-INF/mods.toml 2>/dev/null | awk '/^[[:space:]]*\[/ { m = ($0 ~ /^[[:space:]]*\[\[mods\]\]/); if (m) print "[[mods]]" } m && /^[[:space:]]*(modId|version|displayName|displayTest)[[:space:]]*=/'
+  unzip -p "$f" mcmod.info 2>/dev/null | grep -E '"(modid|name|version)"' || true
+  unzip -p "$f" META-INF/MANIFEST.MF 2>/dev/null | grep '^Implementation-Version:' || true
+done`
+
+// pixelmonModID is the mod ID of Pixelmon
+const pixelmonModID = "pixelmon"
+
+var (
+	// serverOnlyMods are the IDs of mods players don't need, set by PIXELMON_SERVER_ONLY_MODS
+	serverOnlyMods = strings.Split(getEnv("PIXELMON_SERVER_ONLY_MODS", ""), ",")
+
+	// forgeVersion matches the Minecraft and Forge versions in the name of a Forge install, like 1.16.5-36.2.39
+	forgeVersion = regexp.MustCompile(`(\d+\.\d+(?:\.\d+)?)-(\d+\.\d+\.\d+)`)
+
+	// tomlValue matches a key and its quoted value in mods.toml
+	tomlValue = regexp.MustCompile(`^\s*(\w+)\s*=\s*"([^"]*)"`)
+
+	// jsonValue matches a key and its string value in mcmod.info
+	jsonValue = regexp.MustCompile(`"(modid|name|version)"\s*:\s*"([^"]*)"`)
+)
+
+// Mod is a mod in a jar in the server's mods directory
+type Mod struct {
+	File    string
+	ID      string
+	Name    string
+	Version string
+
+	// ClientRequired is set unless the mod says clients don't need it or it is in PIXELMON_SERVER_ONLY_MODS
+	ClientRequired bool
+}
+
+// ModList is the Forge install and the mods of the server
+type ModList struct {
+	Minecraft string
+	Forge     string
+	Mods      []Mod
+}
+
+// Pixelmon returns the Pixelmon mod, if it is installed
+func (l ModList) Pixelmon() (Mod, bool) {
+	for _, mod := range l.Mods {
+		if mod.ID == pixelmonModID {
+			return mod, true
+		}
+	}
+
+	return Mod{}, false
+}
+
+// ClientMods returns the mods players need to install
+func (l ModList) ClientMods() []Mod {
+	var mods []Mod
+	for _, mod := range l.Mods {
+		if mod.ClientRequired {
+			mods = append(mods, mod)
+		}
+	}
+
+	return mods
+}
+
+// GetMods reads the Forge version and the mods of the server from its install. The instance must be running.
+func GetMods(ctx context.Context, srv Server) (ModList, error) {
+	b, err := runningBackend(ctx, srv)
+	if err != nil {
+		return ModList{}, err
+	}
+
+	output, err := b.shell(ctx, modsScript)
+	if err != nil {
+		return ModList{}, err
+	}
+
+	return parseMods(output), nil
+}
+
+// parseMods parses the output of modsScript
+func parseMods(output string) ModList {
+	var list ModList
+
+	// jar collects the metadata of a jar until the next one starts
+	var jar struct {
+		file     string
+		mods     []Mod
+		info     Mod
+		manifest string
+	}
+	flush := func() {
+		if jar.file == "" {
+			return
+		}
+
+		mods := jar.mods
+		if len(mods) == 0 {
+			mods = []Mod{jar.info}
+		}
+		for _, mod := range mods {
+			mod.File = jar.file
+			if mod.ID == "" {
+				mod.ID = strings.TrimSuffix(jar.file, path.Ext(jar.file))
+			}
+			if mod.Name == "" {
+				mod.Name = mod.ID
+			}
+			// The version is usually filled in from the manifest when the mod is loaded
+			if mod.Version == "" || strings.HasPrefix(mod.Version, "${") {
+				mod.Version = jar.manifest
+			}
+			mod.ClientRequired = mod.ClientRequired && !isServerOnlyMod(mod.ID)
+
+			list.Mods = append(list.Mods, mod)
+		}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "forge: "):
+			if match := forgeVersion.FindStringSubmatch(line); match != nil && list.Forge == "" {
+				list.Minecraft, list.Forge = match[1], match[2]
+			}
+		case strings.HasPrefix(line, "jar: "):
+			flush()
+			jar.file, jar.mods, jar.info, jar.manifest = strings.TrimPrefix(line, "jar: "), nil, Mod{ClientRequired: true}, ""
+		case line == "[[mods]]":
+			jar.mods = append(jar.mods, Mod{ClientRequired: true})
+		case strings.HasPrefix(line, "Implementation-Version:"):
+			jar.manifest = strings.TrimSpace(strings.TrimPrefix(line, "Implementation-Version:"))
+		default:
+			if match := tomlValue.FindStringSubmatch(line); match != nil && len(jar.mods) > 0 {
+				mod := &jar.mods[len(jar.mods)-1]
+				switch match[1] {
+				case "modId":
+					mod.ID = match[2]
+				case "displayName":
+					mod.Name = match[2]
+				case "version":
+					mod.Version = match[2]
+				case "displayTest":
+					// Mods that work without being on the client don't check its version
+					mod.ClientRequired = match[2] != "IGNORE_SERVER_VERSION" && match[2] != "IGNORE_ALL_VERSION"
+				}
+				continue
+			}
+
+			// mcmod.info can list several mods, so only the first is used
+			for _, match := range jsonValue.FindAllStringSubmatch(line, -1) {
+				switch {
+				case match[1] == "modid" && jar.info.ID == "":
+					jar.info.ID = match[2]
+				case match[1] == "name" && jar.info.Name == "":
+					jar.info.Name = match[2]
+				case match[1] == "version" && jar.info.Version == "":
+					jar.info.Version = match[2]
+				}
+			}
+		}
+	}
+	flush()
+
+	sort.Slice(list.Mods, func(a, b int) bool {
+		return strings.ToLower(list.Mods[a].Name) < strings.ToLower(list.Mods[b].Name)
+	})
+
+	return list
+}
+
+// isServerOnlyMod checks if the mod is in PIXELMON_SERVER_ONLY_MODS
+func isServerOnlyMod(id string) bool {
+	for _, serverOnly := range serverOnlyMods {
+		if strings.EqualFold(strings.TrimSpace(serverOnly), id) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package pixelmon
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMods(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     string
+		serverOnly []string
+		want       ModList
+	}{
+		{name: "empty", output: "", want: ModList{}},
+		{
+			name:   "forge library install",
+			output: "forge: libraries/net/minecraftforge/forge/1.16.5-36.2.39/\n",
+			want:   ModList{Minecraft: "1.16.5", Forge: "36.2.39"},
+		},
+		{
+			name:   "forge jar install",
+			output: "forge: forge-1.12.2-14.23.5.2860.jar\nforge: forge-1.16.5-36.2.39.jar\n",
+			want:   ModList{Minecraft: "1.12.2", Forge: "14.23.5"},
+		},
+		{
+			name: "mods.toml",
+			output: "jar: Pixelmon-1.16.5-9.1.12-server.jar\n" +
+				"[[mods]]\n" +
+				`modId="pixelmon"` + "\n" +
+				`version="9.1.12"` + "\n" +
+				`displayName="Pixelmon"` + "\n",
+			want: ModList{Mods: []Mod{
+				{File: "Pixelmon-1.16.5-9.1.12-server.jar", ID: "pixelmon", Name: "Pixelmon", Version: "9.1.12", ClientRequired: true},
+			}},
+		},
+		{
+			name: "several mods in a jar",
+			output: "jar: bundle.jar\n" +
+				"[[mods]]\n" +
+				`  modId = "first"` + "\n" +
+				"[[mods]]\n" +
+				`  modId = "second"` + "\n" +
+				`  displayName = "Second"` + "\n",
+			want: ModList{Mods: []Mod{
+				{File: "bundle.jar", ID: "first", Name: "first", ClientRequired: true},
+				{File: "bundle.jar", ID: "second", Name: "Second", ClientRequired: true},
+			}},
+		},
+		{
+			name: "displayTest",
+			output: "jar: spark.jar\n[[mods]]\nmodId=\"spark\"\ndisplayTest=\"IGNORE_SERVER_VERSION\"\n" +
+				"jar: chunky.jar\n[[mods]]\nmodId=\"chunky\"\ndisplayTest=\"IGNORE_ALL_VERSION\"\n" +
+				"jar: jei.jar\n[[mods]]\nmodId=\"jei\"\ndisplayTest=\"MATCH_VERSION\"\n",
+			want: ModList{Mods: []Mod{
+				{File: "chunky.jar", ID: "chunky", Name: "chunky"},
+				{File: "jei.jar", ID: "jei", Name: "jei", ClientRequired: true},
+				{File: "spark.jar", ID: "spark", Name: "spark"},
+			}},
+		},
+		{
+			name: "version from the manifest",
+			output: "jar: jei.jar\n" +
+				"[[mods]]\n" +
+				`modId="jei"` + "\n" +
+				`version="${file.jarVersion}"` + "\n" +
+				"Implementation-Version: 7.7.1.153\n",
+			want: ModList{Mods: []Mod{
+				{File: "jei.jar", ID: "jei", Name: "jei", Version: "7.7.1.153", ClientRequired: true},
+			}},
+		},
+		{
+			name: "mcmod.info",
+			output: "jar: Pixelmon-1.12.2-8.4.3-universal.jar\n" +
+				`"modid": "pixelmon", "name": "Pixelmon Reforged", "version": "8.4.3",` + "\n" +
+				`"modid": "pixelmonextras", "name": "Extras", "version": "1.0",` + "\n",
+			want: ModList{Mods: []Mod{
+				{File: "Pixelmon-1.12.2-8.4.3-universal.jar", ID: "pixelmon", Name: "Pixelmon Reforged", Version: "8.4.3", ClientRequired: true},
+			}},
+		},
+		{
+			name:   "no metadata",
+			output: "jar: mystery-1.0.jar\n",
+			want: ModList{Mods: []Mod{
+				{File: "mystery-1.0.jar", ID: "mystery-1.0", Name: "mystery-1.0", ClientRequired: true},
+			}},
+		},
+		{
+			name: "server only mods",
+			output: "jar: spark.jar\n[[mods]]\nmodId=\"spark\"\n" +
+				"jar: jei.jar\n[[mods]]\nmodId=\"jei\"\n",
+			serverOnly: []string{" Spark", "luckperms"},
+			want: ModList{Mods: []Mod{
+				{File: "jei.jar", ID: "jei", Name: "jei", ClientRequired: true},
+				{File: "spark.jar", ID: "spark", Name: "spark"},
+			}},
+		},
+		{
+			name: "sorted by name",
+			output: "forge: libraries/net/minecraftforge/forge/1.16.5-36.2.39/\n" +
+				"jar: b.jar\n[[mods]]\nmodId=\"b\"\ndisplayName=\"beta\"\n" +
+				"jar: a.jar\n[[mods]]\nmodId=\"a\"\ndisplayName=\"Alpha\"\n" +
+				"jar: c.jar\n[[mods]]\nmodId=\"c\"\ndisplayName=\"Gamma\"\n",
+			want: ModList{Minecraft: "1.16.5", Forge: "36.2.39", Mods: []Mod{
+				{File: "a.jar", ID: "a", Name: "Alpha", ClientRequired: true},
+				{File: "b.jar", ID: "b", Name: "beta", ClientRequired: true},
+				{File: "c.jar", ID: "c", Name: "Gamma", ClientRequired: true},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(previous []string) { serverOnlyMods = previous }(serverOnlyMods)
+			serverOnlyMods = tt.serverOnly
+
+			if got := parseMods(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMods() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModList(t *testing.T) {
+	list := ModList{Mods: []Mod{
+		{ID: "jei", ClientRequired: true},
+		{ID: "pixelmon", ClientRequired: true},
+		{ID: "spark"},
+	}}
+
+	if mod, ok := list.Pixelmon(); !ok || mod.ID != "pixelmon" {
+		t.Errorf("Pixelmon() = %+v, %v, want pixelmon", mod, ok)
+	}
+	if _, ok := (ModList{Mods: list.Mods[2:]}).Pixelmon(); ok {
+		t.Error("Pixelmon() found pixelmon in a list without it")
+	}
+
+	if got, want := list.ClientMods(), list.Mods[:2]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ClientMods() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,214 @@
+package pixelmon
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// processCommand starts the Minecraft service in the server's directory, set by PIXELMON_PROCESS_COMMAND
+var processCommand = getEnv("PIXELMON_PROCESS_COMMAND", "./start.sh")
+
+// process is a Minecraft service the bot started and supervises
+type process struct {
+	cmd     *exec.Cmd
+	started time.Time
+	exited  chan struct{}
+
+	// stdin is the service's console
+	stdin   io.WriteCloser
+	stdinMu sync.Mutex
+}
+
+// processes are the running processes by server key. A process is removed once it exits.
+var (
+	processes   = make(map[string]*process)
+	processesMu sync.Mutex
+)
+
+// processBackend runs the server as a process of the bot, in the directory that is the instance ID. The process is
+// the Minecraft service, so it stops when the service does, and commands are written to its console.
+type processBackend struct {
+	srv Server
+}
+
+// running returns the server's process, or nil if it isn't running
+func (b processBackend) running() *process {
+	processesMu.Lock()
+	defer processesMu.Unlock()
+
+	return processes[b.srv.Key()]
+}
+
+func (b processBackend) describe(ctx context.Context) (instance, error) {
+	if info, err := os.Stat(b.srv.InstanceID); err != nil || !info.IsDir() {
+		return instance{}, ErrNotFound
+	}
+
+	result := instance{
+		State:    instanceStopped,
+		Type:     BackendProcess,
+		PublicIP: b.srv.PublicIP,
+	}
+	if p := b.running(); p != nil {
+		result.ID = strconv.Itoa(p.cmd.Process.Pid)
+		result.State = instanceRunning
+		result.LaunchTime = p.started
+	}
+
+	return result, nil
+}
+
+func (b processBackend) start(ctx context.Context) error {
+	processesMu.Lock()
+	defer processesMu.Unlock()
+
+	key := b.srv.Key()
+	if processes[key] != nil {
+		return nil
+	}
+
+	// The process outlives the request that starts it, so it isn't tied to ctx. exec makes the service the shell's
+	// process so it gets the console and signals.
+	cmd := exec.Command("sh", "-c", "exec "+processCommand)
+	cmd.Dir = b.srv.InstanceID
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start process: %w", err)
+	}
+
+	p := &process{
+		cmd:     cmd,
+		started: time.Now(),
+		exited:  make(chan struct{}),
+		stdin:   stdin,
+	}
+	processes[key] = p
+	log.Printf("Started %v process %v", b.srv.Name, cmd.Process.Pid)
+
+	go func() {
+		err := cmd.Wait()
+		log.Printf("%v process %v exited: %v", b.srv.Name, cmd.Process.Pid, cmd.ProcessState)
+		if err != nil {
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				log.Printf("Error waiting for %v process: %v", b.srv.Name, err)
+			}
+		}
+
+		processesMu.Lock()
+		if processes[key] == p {
+			delete(processes, key)
+		}
+		processesMu.Unlock()
+		close(p.exited)
+	}()
+
+	return nil
+}
+
+func (b processBackend) stop(ctx context.Context) error {
+	p := b.running()
+	if p == nil {
+		return nil
+	}
+
+	// Minecraft saves the world when it is interrupted
+	if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(localStopTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-p.exited:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		log.Printf("Killing %v process %v after %v", b.srv.Name, p.cmd.Process.Pid, localStopTimeout)
+		return p.cmd.Process.Kill()
+	}
+}
+
+func (b processBackend) startService(ctx context.Context) error {
+	// Starting the process starts the service, and does nothing if it is running
+	return b.start(ctx)
+}
+
+func (b processBackend) restartService(ctx context.Context) error {
+	// Wait for the process to exit once the service stopped
+	if p := b.running(); p != nil {
+		select {
+		case <-p.exited:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return b.start(ctx)
+}
+
+func (b processBackend) console(ctx context.Context, commands ...string) error {
+	p := b.running()
+	if p == nil {
+		return ErrOffline
+	}
+
+	p.stdinMu.Lock()
+	defer p.stdinMu.Unlock()
+
+	for _, command := range commands {
+		if _, err := io.WriteString(p.stdin, command+"\n"); err != nil {
+			return fmt.Errorf("failed to write to %v console: %w", b.srv.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (b processBackend) query(ctx context.Context, command string) (string, error) {
+	// The console's output isn't kept, so responses can't be told apart from the rest of the log
+	return "", ErrNotSupported
+}
+
+func (b processBackend) readFile(ctx context.Context, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(b.srv.InstanceID, name))
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (b processBackend) writeFile(ctx context.Context, name string, contents string) error {
+	return os.WriteFile(filepath.Join(b.srv.InstanceID, name), []byte(contents), 0o644)
+}
+
+func (b processBackend) shell(ctx context.Context, script string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", script)
+	cmd.Dir = b.srv.InstanceID
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.String(), nil
+}
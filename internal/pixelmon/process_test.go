@@ -0,0 +1,150 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// dummyServer stands in for the Minecraft service. It logs its console, keeps a whitelist and exits on stop or when
+// it is interrupted.
+const dummyServer = `#!/bin/sh
+trap 'echo interrupted >> console.log; exit 0' INT
+echo '[]' > whitelist.json
+while read -r line; do
+	echo "$line" >> console.log
+	case "$line" in
+	"whitelist add "*) printf '[{"name": "%s"}]\n' "${line#whitelist add }" > whitelist.json ;;
+	stop) exit 0 ;;
+	esac
+done
+`
+
+func TestProcessServer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "start.sh"), []byte(dummyServer), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	srv := Server{Name: "Dummy", InstanceID: dir, Backend: BackendProcess, Domain: "example.com", Subdomain: "dummy"}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	assertRunning(t, srv, false)
+	if err := Stop(ctx, srv); !errors.Is(err, ErrOffline) {
+		t.Errorf("Stop() of a stopped server error = %v, want ErrOffline", err)
+	}
+
+	if err := Start(ctx, srv); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer Stop(context.Background(), srv)
+	assertRunning(t, srv, true)
+	if err := Start(ctx, srv); !errors.Is(err, ErrOnline) {
+		t.Errorf("Start() of a running server error = %v, want ErrOnline", err)
+	}
+
+	if err := SendMessage(ctx, srv, "hello"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	if err := AddToWhitelist(ctx, srv, "Ash"); err != nil {
+		t.Fatalf("AddToWhitelist() error = %v", err)
+	}
+	eventually(t, "the whitelist has Ash", func() bool {
+		names, err := GetWhitelist(ctx, srv)
+		return err == nil && reflect.DeepEqual(names, []string{"Ash"})
+	})
+
+	// The server is stopped by interrupting it, so it saves the world
+	if err := Stop(ctx, srv); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	assertRunning(t, srv, false)
+	if got, want := readConsole(t, dir), "say hello\nwhitelist add Ash\ninterrupted\n"; got != want {
+		t.Errorf("console is %q, want %q", got, want)
+	}
+
+	// The process exits when the service stops, like after /stop in the game
+	if err := Start(ctx, srv); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.console(ctx, "stop"); err != nil {
+		t.Fatalf("console() error = %v", err)
+	}
+	eventually(t, "the process exited", func() bool {
+		running, err := GetStatus(ctx, srv)
+		return err == nil && !running
+	})
+	if err := b.console(ctx, "say hello"); !errors.Is(err, ErrOffline) {
+		t.Errorf("console() of a stopped server error = %v, want ErrOffline", err)
+	}
+}
+
+func TestProcessServerWithoutDNS(t *testing.T) {
+	srv := Server{Name: "Dummy", InstanceID: t.TempDir(), Backend: BackendProcess, Domain: "example.com", Subdomain: "dummy"}
+	ctx := context.Background()
+
+	// Without a public IP there are no records, so none of the DNS stages need a provider
+	srv.DNSProvider = "unknown"
+	if changeID, err := createPixelmonDNSEntry(ctx, aws.Config{}, srv, instance{}); changeID != "" || err != nil {
+		t.Errorf("createPixelmonDNSEntry() = %q, %v, want no change", changeID, err)
+	}
+	if err := waitForDNSChange(ctx, aws.Config{}, srv, ""); err != nil {
+		t.Errorf("waitForDNSChange() error = %v", err)
+	}
+	if err := verifyDNS(ctx, srv, ""); err != nil {
+		t.Errorf("verifyDNS() error = %v", err)
+	}
+	if err := deletePixelmonDNSEntry(ctx, aws.Config{}, srv); err != nil {
+		t.Errorf("deletePixelmonDNSEntry() error = %v", err)
+	}
+
+	srv.PublicIP = "203.0.113.10"
+	if _, err := createPixelmonDNSEntry(ctx, aws.Config{}, srv, instance{PublicIP: srv.PublicIP}); err == nil || !strings.Contains(err.Error(), "unknown DNS provider") {
+		t.Errorf("createPixelmonDNSEntry() with a public IP error = %v, want unknown DNS provider", err)
+	}
+}
+
+// assertRunning checks whether the server's instance is running
+func assertRunning(t *testing.T, srv Server, want bool) {
+	t.Helper()
+
+	running, err := GetStatus(context.Background(), srv)
+	if err != nil || running != want {
+		t.Fatalf("GetStatus() = %v, %v, want %v", running, err, want)
+	}
+}
+
+// eventually waits for the condition, since the process handles its console in the background
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+
+	for deadline := time.Now().Add(10 * time.Second); !condition(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %v", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readConsole returns the commands the dummy server got
+func readConsole(t *testing.T, dir string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(dir, "console.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,123 @@
+package pixelmon
+
+import (
+	"context"
+	"log"
+
+	"github.com/kn-lim/seigetsu-bot/internal/retry"
+)
+
+// Step is a call made by a lifecycle operation. Steps are named for progress messages.
+type Step string
+
+const (
+	StepDescribeInstance Step = "describe_instance"
+	StepStartInstance    Step = "start_instance"
+	StepStopInstance     Step = "stop_instance"
+	StepModifyInstance   Step = "modify_instance"
+	StepSendCommand      Step = "send_command"
+	StepCommandOutput    Step = "command_output"
+	StepUpdateDNS        Step = "update_dns"
+	StepCheckDNS         Step = "check_dns"
+	StepCheckStatus      Step = "check_status"
+)
+
+// retryPolicy is how calls to AWS and mcstatus.io are retried
+var retryPolicy = retry.Default
+
+// sendPolicy is how calls that must not run twice, like running commands, are retried. They are only retried if they
+// certainly weren't acted on.
+var sendPolicy = func() retry.Policy {
+	p := retryPolicy
+	p.Retryable = retry.IsUnsent
+	return p
+}()
+
+// Progress is an update on a lifecycle operation in progress
+type Progress struct {
+	Server Server
+
+	// Stage is set when a stage starts or is being rolled back
+	Stage       Stage
+	RollingBack bool
+
+	// DNS is set while waiting for the DNS record
+	DNS *DNSStatus
+
+	// Step is set when a call is being retried
+	Step Step
+
+	// Retry is the attempt that failed if the step is being retried
+	Retry    *retry.Attempt
+	Attempts int
+}
+
+// StepError is the error of the step that made an operation fail
+type StepError struct {
+	Step Step
+	Err  error
+}
+
+func (e *StepError) Error() string {
+	return string(e.Step) + ": " + e.Err.Error()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context that reports the progress of the operations using it to f
+func WithProgress(ctx context.Context, f func(Progress)) context.Context {
+	return context.WithValue(ctx, progressKey{}, f)
+}
+
+// reportProgress reports the progress to the function from WithProgress, if any
+func reportProgress(ctx context.Context, p Progress) {
+	if f, ok := ctx.Value(progressKey{}).(func(Progress)); ok {
+		f(p)
+	}
+}
+
+// call makes the step's call, retrying it if it fails with a retryable error
+func call(ctx context.Context, srv Server, step Step, f func(ctx context.Context) error) error {
+	return callWith(ctx, srv, step, retryPolicy, f)
+}
+
+// callWith makes the step's call, retrying it as the policy says
+func callWith(ctx context.Context, srv Server, step Step, policy retry.Policy, f func(ctx context.Context) error) error {
+	err := retry.Do(ctx, policy, f, func(attempt retry.Attempt) {
+		log.Printf("Retrying %v of %v in %v: %v", step, srv.Name, attempt.Wait, attempt.Err)
+		reportProgress(ctx, Progress{Server: srv, Step: step, Retry: &attempt, Attempts: policy.Attempts})
+	})
+	if err != nil {
+		return &StepError{Step: step, Err: err}
+	}
+
+	return nil
+}
+
+// callValue is call for calls that return a value
+func callValue[T any](ctx context.Context, srv Server, step Step, f func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := call(ctx, srv, step, func(ctx context.Context) error {
+		var err error
+		value, err = f(ctx)
+		return err
+	})
+
+	return value, err
+}
+
+// sendValue is callValue for calls that must not run twice, which are only retried if they weren't sent
+func sendValue[T any](ctx context.Context, srv Server, step Step, f func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := callWith(ctx, srv, step, sendPolicy, func(ctx context.Context) error {
+		var err error
+		value, err = f(ctx)
+		return err
+	})
+
+	return value, err
+}
@@ -0,0 +1,342 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const propertiesFile = "server.properties"
+
+// Kinds of values a server property or game rule accepts
+const (
+	KindBool   = "bool"
+	KindInt    = "int"
+	KindEnum   = "enum"
+	KindString = "string"
+)
+
+var (
+	ErrUnknownProperty = errors.New("unknown server property")
+	ErrInvalidValue    = errors.New("invalid value")
+)
+
+// Schema describes the values a server property or game rule accepts
+type Schema struct {
+	Kind string
+
+	// Min and Max bound KindInt values
+	Min int
+	Max int
+
+	// Values are the KindEnum values
+	Values []string
+}
+
+// Validate checks that the value fits the schema, returning ErrInvalidValue if it doesn't
+func (s Schema) Validate(value string) error {
+	valid := false
+	switch s.Kind {
+	case KindBool:
+		valid = value == "true" || value == "false"
+	case KindInt:
+		n, err := strconv.Atoi(value)
+		valid = err == nil && n >= s.Min && n <= s.Max
+	case KindEnum:
+		for _, v := range s.Values {
+			if value == v {
+				valid = true
+			}
+		}
+	case KindString:
+		valid = !strings.ContainsAny(value, "\r\n")
+	}
+
+	if !valid {
+		return fmt.Errorf("%w %q", ErrInvalidValue, value)
+	}
+
+	return nil
+}
+
+// Property is a known key of server.properties
+type Property struct {
+	Schema
+
+	// apply returns the Minecraft command that applies the value to the running service, if there is one. Other
+	// properties are only read when the service starts.
+	apply func(value string) string
+}
+
+func boolSchema() Schema {
+	return Schema{Kind: KindBool}
+}
+
+func intSchema(min int, max int) Schema {
+	return Schema{Kind: KindInt, Min: min, Max: max}
+}
+
+func enumSchema(values ...string) Schema {
+	return Schema{Kind: KindEnum, Values: values}
+}
+
+// properties are the server.properties keys that can be changed
+var properties = map[string]Property{
+	"allow-flight":         {Schema: boolSchema()},
+	"allow-nether":         {Schema: boolSchema()},
+	"difficulty":           {Schema: enumSchema("peaceful", "easy", "normal", "hard"), apply: applyCommand("difficulty")},
+	"enable-command-block": {Schema: boolSchema()},
+	"enforce-whitelist":    {Schema: boolSchema()},
+	"force-gamemode":       {Schema: boolSchema()},
+	"gamemode":             {Schema: enumSchema("survival", "creative", "adventure", "spectator"), apply: applyCommand("defaultgamemode")},
+	"generate-structures":  {Schema: boolSchema()},
+	"hardcore":             {Schema: boolSchema()},
+	"level-seed":           {Schema: Schema{Kind: KindString}},
+	"max-players":          {Schema: intSchema(1, math.MaxInt32)},
+	"max-tick-time":        {Schema: intSchema(-1, math.MaxInt32)},
+	"max-world-size":       {Schema: intSchema(1, 29999984)},
+	"motd":                 {Schema: Schema{Kind: KindString}},
+	"online-mode":          {Schema: boolSchema()},
+	"player-idle-timeout":  {Schema: intSchema(0, math.MaxInt32), apply: applyCommand("setidletimeout")},
+	"pvp":                  {Schema: boolSchema()},
+	"simulation-distance":  {Schema: intSchema(3, 32)},
+	"spawn-animals":        {Schema: boolSchema()},
+	"spawn-monsters":       {Schema: boolSchema()},
+	"spawn-npcs":           {Schema: boolSchema()},
+	"spawn-protection":     {Schema: intSchema(0, math.MaxInt32)},
+	"view-distance":        {Schema: intSchema(3, 32)},
+	"white-list": {Schema: boolSchema(), apply: func(value string) string {
+		if value == "true" {
+			return "whitelist on"
+		}
+		return "whitelist off"
+	}},
+}
+
+// applyCommand returns an apply function that passes the value to the Minecraft command
+func applyCommand(name string) func(value string) string {
+	return func(value string) string {
+		return name + " " + value
+	}
+}
+
+// PropertyKeys returns the server.properties keys that can be changed, in order
+func PropertyKeys() []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// LookupProperty returns the schema of the server.properties key
+func LookupProperty(key string) (Property, bool) {
+	property, ok := properties[key]
+	return property, ok
+}
+
+// GetProperty returns the value of the key in the server's server.properties. It is false if the key isn't set, so
+// the service uses its default.
+func GetProperty(ctx context.Context, srv Server, key string) (string, bool, error) {
+	if _, ok := properties[key]; !ok {
+		return "", false, ErrUnknownProperty
+	}
+
+	b, err := runningBackend(ctx, srv)
+	if err != nil {
+		return "", false, err
+	}
+
+	contents, err := b.readFile(ctx, propertiesFile)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, line := range strings.Split(contents, "\n") {
+		if k, v, ok := parsePropertyLine(line); ok && k == key {
+			return v, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// SetProperty changes the value of the key in the server's server.properties. If the service is online, the value is
+// applied to it when a command can, and otherwise restart is set since the service only reads the file on start.
+func SetProperty(ctx context.Context, srv Server, key string, value string) (restart bool, err error) {
+	property, ok := properties[key]
+	if !ok {
+		return false, ErrUnknownProperty
+	}
+	if err := property.Validate(value); err != nil {
+		return false, err
+	}
+
+	b, err := runningBackend(ctx, srv)
+	if err != nil {
+		return false, err
+	}
+
+	contents, err := b.readFile(ctx, propertiesFile)
+	if err != nil {
+		return false, err
+	}
+
+	if err := b.writeFile(ctx, propertiesFile, replaceProperty(contents, key, value)); err != nil {
+		return false, err
+	}
+	log.Printf("Set %v to %q on %v", key, value, srv.Name)
+
+	// The file was changed, so if it isn't known whether the service is online it may need a restart
+	status, err := getMCStatus(ctx, srv)
+	if err != nil {
+		log.Printf("Error checking if %v is online: %v", srv.Name, err)
+		return true, nil
+	}
+	if !status.Online {
+		return false, nil
+	}
+	if property.apply == nil {
+		return true, nil
+	}
+
+	return false, b.console(ctx, property.apply(value))
+}
+
+// replaceProperty returns the contents of server.properties with the key set to the value, replacing the lines that
+// set it or adding one at the end
+func replaceProperty(contents string, key string, value string) string {
+	lines := strings.Split(strings.TrimRight(contents, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		lines = nil
+	}
+
+	found := false
+	for n, line := range lines {
+		if k, _, ok := parsePropertyLine(line); ok && k == key {
+			lines[n] = key + "=" + escapeProperty(value)
+			found = true
+		}
+	}
+	if !found {
+		lines = append(lines, key+"="+escapeProperty(value))
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// parsePropertyLine returns the key and value of a server.properties line, or false if it is a comment or blank. Like
+// Java's Properties, the key ends at the first unescaped '=', ':' or whitespace, and escapes are undone.
+func parsePropertyLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+		return "", "", false
+	}
+
+	end := len(line)
+	for n := 0; n < len(line); n++ {
+		if line[n] == '\\' {
+			n++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[n]) >= 0 {
+			end = n
+			break
+		}
+	}
+
+	value := strings.TrimLeft(line[end:], " \t\f")
+	if strings.HasPrefix(value, "=") || strings.HasPrefix(value, ":") {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+
+	return unescapeProperty(line[:end]), unescapeProperty(value), true
+}
+
+// escapeProperty escapes a server.properties value the way Java's Properties writes it
+func escapeProperty(value string) string {
+	var b strings.Builder
+	for n, r := range value {
+		switch r {
+		case '\\', '=', ':', '#', '!':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case ' ':
+			if n == 0 {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// unescapeProperty undoes the escapes of a server.properties key or value
+func unescapeProperty(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for n := 0; n < len(s); n++ {
+		if s[n] != '\\' || n+1 == len(s) {
+			b.WriteByte(s[n])
+			continue
+		}
+
+		n++
+		switch s[n] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if r, err := strconv.ParseUint(s[n+1:min(n+5, len(s))], 16, 16); err == nil && n+5 <= len(s) {
+				b.WriteRune(rune(r))
+				n += 4
+				continue
+			}
+			b.WriteByte('u')
+		default:
+			b.WriteByte(s[n])
+		}
+	}
+
+	return b.String()
+}
+
+// runningBackend returns the server's backend, or ErrOffline if its instance isn't running
+func runningBackend(ctx context.Context, srv Server) (backend, error) {
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := b.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if instance.State != instanceRunning {
+		return nil, ErrOffline
+	}
+
+	return b, nil
+}
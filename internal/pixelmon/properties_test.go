@@ -0,0 +1,175 @@
+package pixelmon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSchemaValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  Schema
+		value   string
+		wantErr bool
+	}{
+		{name: "true", schema: boolSchema(), value: "true"},
+		{name: "false", schema: boolSchema(), value: "false"},
+		{name: "capitalized bool", schema: boolSchema(), value: "True", wantErr: true},
+		{name: "number as bool", schema: boolSchema(), value: "1", wantErr: true},
+		{name: "int", schema: intSchema(3, 32), value: "10"},
+		{name: "int at min", schema: intSchema(3, 32), value: "3"},
+		{name: "int at max", schema: intSchema(3, 32), value: "32"},
+		{name: "negative int", schema: intSchema(-1, 10), value: "-1"},
+		{name: "int below min", schema: intSchema(3, 32), value: "2", wantErr: true},
+		{name: "int above max", schema: intSchema(3, 32), value: "33", wantErr: true},
+		{name: "not an int", schema: intSchema(3, 32), value: "ten", wantErr: true},
+		{name: "decimal", schema: intSchema(3, 32), value: "10.5", wantErr: true},
+		{name: "enum", schema: enumSchema("peaceful", "easy"), value: "easy"},
+		{name: "not in enum", schema: enumSchema("peaceful", "easy"), value: "hard", wantErr: true},
+		{name: "enum case", schema: enumSchema("peaceful", "easy"), value: "Easy", wantErr: true},
+		{name: "string", schema: Schema{Kind: KindString}, value: "A Pixelmon server = fun"},
+		{name: "empty string", schema: Schema{Kind: KindString}, value: ""},
+		{name: "string with newline", schema: Schema{Kind: KindString}, value: "a\nb", wantErr: true},
+		{name: "unknown kind", schema: Schema{}, value: "true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schema.Validate(tt.value)
+			if (err != nil) != tt.wantErr || err != nil && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePropertyLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantKey   string
+		wantValue string
+		ok        bool
+	}{
+		{name: "property", line: "difficulty=easy", wantKey: "difficulty", wantValue: "easy", ok: true},
+		{name: "spaces", line: "  max-players = 20  ", wantKey: "max-players", wantValue: "20", ok: true},
+		{name: "colon", line: "pvp: true", wantKey: "pvp", wantValue: "true", ok: true},
+		{name: "whitespace separator", line: "pvp true", wantKey: "pvp", wantValue: "true", ok: true},
+		{name: "blank value", line: "level-seed=", wantKey: "level-seed", ok: true},
+		{name: "no value", line: "level-seed", wantKey: "level-seed", ok: true},
+		{name: "escaped equals", line: `motd=1 + 1 \= 2`, wantKey: "motd", wantValue: "1 + 1 = 2", ok: true},
+		{name: "escaped colon", line: `motd=Time\: now`, wantKey: "motd", wantValue: "Time: now", ok: true},
+		{name: "unescaped equals in value", line: "motd=a=b", wantKey: "motd", wantValue: "a=b", ok: true},
+		{name: "escaped key", line: `a\=b=c`, wantKey: "a=b", wantValue: "c", ok: true},
+		{name: "escaped backslash", line: `motd=C\:\\Pixelmon`, wantKey: "motd", wantValue: `C:\Pixelmon`, ok: true},
+		{name: "unicode escape", line: `motd=Pok\u00e9mon`, wantKey: "motd", wantValue: "Pokémon", ok: true},
+		{name: "invalid unicode escape", line: `motd=\u00`, wantKey: "motd", wantValue: "u00", ok: true},
+		{name: "hash comment", line: "#Minecraft server properties"},
+		{name: "indented comment", line: "  # difficulty=hard"},
+		{name: "bang comment", line: "! difficulty=hard"},
+		{name: "blank", line: "   "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, value, ok := parsePropertyLine(tt.line)
+			if key != tt.wantKey || value != tt.wantValue || ok != tt.ok {
+				t.Errorf("parsePropertyLine(%q) = %q, %q, %v, want %q, %q, %v", tt.line, key, value, ok, tt.wantKey, tt.wantValue, tt.ok)
+			}
+		})
+	}
+}
+
+func TestReplaceProperty(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		key      string
+		value    string
+		want     string
+	}{
+		{
+			name:     "replace",
+			contents: "#Minecraft server properties\ndifficulty=easy\npvp=true\n",
+			key:      "difficulty",
+			value:    "hard",
+			want:     "#Minecraft server properties\ndifficulty=hard\npvp=true\n",
+		},
+		{
+			name:     "append",
+			contents: "difficulty=easy\n",
+			key:      "pvp",
+			value:    "false",
+			want:     "difficulty=easy\npvp=false\n",
+		},
+		{
+			name:     "append without trailing newline",
+			contents: "difficulty=easy",
+			key:      "pvp",
+			value:    "false",
+			want:     "difficulty=easy\npvp=false\n",
+		},
+		{
+			name:     "empty file",
+			contents: "",
+			key:      "pvp",
+			value:    "false",
+			want:     "pvp=false\n",
+		},
+		{
+			name:     "comments are kept",
+			contents: "#difficulty=peaceful\ndifficulty=easy\n",
+			key:      "difficulty",
+			value:    "hard",
+			want:     "#difficulty=peaceful\ndifficulty=hard\n",
+		},
+		{
+			name:     "duplicate keys",
+			contents: "difficulty=easy\npvp=true\ndifficulty = normal\n",
+			key:      "difficulty",
+			value:    "hard",
+			want:     "difficulty=hard\npvp=true\ndifficulty=hard\n",
+		},
+		{
+			name:     "prefix of another key",
+			contents: "spawn-monsters=true\n",
+			key:      "spawn",
+			value:    "false",
+			want:     "spawn-monsters=true\nspawn=false\n",
+		},
+		{
+			name:     "blank value",
+			contents: "level-seed=1234\n",
+			key:      "level-seed",
+			value:    "",
+			want:     "level-seed=\n",
+		},
+		{
+			name:     "escaped value",
+			contents: "motd=A Minecraft Server\n",
+			key:      "motd",
+			value:    ` Time: 1 = 1 \ #1!`,
+			want:     `motd=\ Time\: 1 \= 1 \\ \#1\!` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := replaceProperty(tt.contents, tt.key, tt.value)
+			if got != tt.want {
+				t.Errorf("replaceProperty() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeProperty(t *testing.T) {
+	values := []string{"", "easy", "A Pixelmon server", " leading space", `1 + 1 = 2`, `C:\Pixelmon`, "#1!", "tab\there", "Pokémon"}
+
+	for _, value := range values {
+		line := "motd=" + escapeProperty(value)
+		if _, got, ok := parsePropertyLine(line); !ok || got != value {
+			t.Errorf("parsePropertyLine(%q) = %q, %v, want %q", line, got, ok, value)
+		}
+	}
+}
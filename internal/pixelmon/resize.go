@@ -0,0 +1,157 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// InstanceType is an EC2 instance type servers can be resized to
+type InstanceType struct {
+	Name string
+
+	// Price is the cost per hour in USD, 0 if unknown
+	Price float64
+}
+
+var (
+	// InstanceTypes are the types servers can be resized to, set by PIXELMON_INSTANCE_TYPES like
+	// "t3.large=0.0832,r5.xlarge=0.252". Prices are optional.
+	InstanceTypes = getInstanceTypes("PIXELMON_INSTANCE_TYPES")
+
+	// DefaultInstanceType is the type resized servers are reverted to, set by PIXELMON_DEFAULT_INSTANCE_TYPE. Servers
+	// are reverted to the type they had if it isn't set.
+	DefaultInstanceType = os.Getenv("PIXELMON_DEFAULT_INSTANCE_TYPE")
+)
+
+var (
+	ErrInstanceType = errors.New("instance type is not allowed")
+	ErrNotSupported = errors.New("not supported by the server's backend")
+	ErrHibernated   = errors.New("instance is hibernated")
+)
+
+// getInstanceTypes reads the instance types from the environment variable, skipping invalid entries
+func getInstanceTypes(key string) []InstanceType {
+	var types []InstanceType
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		name, price, hasPrice := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			continue
+		}
+
+		instanceType := InstanceType{Name: name}
+		if hasPrice {
+			var err error
+			if instanceType.Price, err = strconv.ParseFloat(price, 64); err != nil || instanceType.Price < 0 {
+				log.Printf("Invalid price of %v in %v: %q", name, key, price)
+				continue
+			}
+		}
+		types = append(types, instanceType)
+	}
+
+	return types
+}
+
+// InstancePrice returns the cost per hour of the instance type, if it is known
+func InstancePrice(name string) (float64, bool) {
+	for _, instanceType := range InstanceTypes {
+		if instanceType.Name == name {
+			return instanceType.Price, instanceType.Price > 0
+		}
+	}
+
+	return 0, false
+}
+
+// allowedInstanceType checks if servers can be resized to the instance type. The default type is always allowed so
+// servers can be reverted to it.
+func allowedInstanceType(name string) bool {
+	if name == DefaultInstanceType {
+		return true
+	}
+	for _, instanceType := range InstanceTypes {
+		if instanceType.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Resize changes the type of the server's EC2 instance and returns the type it had. The instance must be stopped,
+// so a stopping instance is waited for and a running one is ErrOnline.
+func Resize(ctx context.Context, srv Server, instanceType string) (string, error) {
+	if !allowedInstanceType(instanceType) {
+		return "", ErrInstanceType
+	}
+
+	return resize(ctx, srv, instanceType)
+}
+
+// Revert changes the type of the server's EC2 instance back to the type it had before it was resized. The type isn't
+// checked against the allowed types, since it is the server's own or the default type.
+func Revert(ctx context.Context, srv Server, instanceType string) error {
+	_, err := resize(ctx, srv, instanceType)
+	return err
+}
+
+// resize changes the type of the server's EC2 instance once it is stopped and returns the type it had. A hibernated
+// instance keeps its memory on its volume, so its type can't be changed until it is started and stopped again.
+func resize(ctx context.Context, srv Server, instanceType string) (string, error) {
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		return "", err
+	}
+	eb, ok := b.(*ec2Backend)
+	if !ok {
+		return "", ErrNotSupported
+	}
+
+	// Wait till Pixelmon EC2 instance is stopped
+	var instance instance
+	err = waitFor(ctx, srv, PhaseInstanceStopped, func(ctx context.Context) (bool, error) {
+		var err error
+		if instance, err = eb.describe(ctx); err != nil {
+			return false, err
+		}
+
+		switch instance.State {
+		case instanceStopped:
+			return true, nil
+		case instanceStopping:
+			return false, nil
+		}
+
+		return false, ErrOnline
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if instance.Hibernated {
+		return "", ErrHibernated
+	}
+
+	previous := instance.Type
+	if previous == instanceType {
+		return previous, nil
+	}
+
+	log.Printf("Resizing %v EC2 instance from %v to %v...", srv.Name, previous, instanceType)
+
+	err = call(ctx, srv, StepModifyInstance, func(ctx context.Context) error {
+		return eb.modifyInstanceType(ctx, instanceType)
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resize pixelmon: %w", err)
+	}
+
+	log.Printf("Resized %v EC2 instance to %v", srv.Name, instanceType)
+
+	return previous, nil
+}
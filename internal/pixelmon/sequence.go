@@ -0,0 +1,112 @@
+package pixelmon
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// RollbackTimeout limits undoing the stages of a failed sequence, which keeps going when the operation is cancelled
+const RollbackTimeout = 5 * time.Minute
+
+// rollbacks is the number of sequences being rolled back
+var rollbacks atomic.Int32
+
+// RollingBack checks if a failed sequence is undoing its stages. The bot should wait for it before exiting, up to
+// RollbackTimeout, so a shutdown doesn't leave the instance running.
+func RollingBack() bool {
+	return rollbacks.Load() > 0
+}
+
+// Stage is a part of a lifecycle operation that can be undone if a later stage fails
+type Stage string
+
+const (
+	StageBootInstance Stage = "boot_instance"
+	StageWaitInstance Stage = "wait_instance"
+	StageCreateDNS    Stage = "create_dns"
+	StageStartService Stage = "start_service"
+	StageWaitDNS      Stage = "wait_dns"
+	StageVerifyDNS    Stage = "verify_dns"
+	StageWaitService  Stage = "wait_service"
+)
+
+// stage runs a stage and, if a later stage fails, undoes it. undo is nil if there is nothing to undo.
+type stage struct {
+	name Stage
+	run  func(ctx context.Context) error
+	undo func(ctx context.Context) error
+}
+
+// SequenceError is the error of a sequence of stages that failed partway
+type SequenceError struct {
+	// Stage is the stage that failed
+	Stage Stage
+	Err   error
+
+	// Undone are the stages that were rolled back, in the order they were undone
+	Undone []Stage
+
+	// NotUndone are the stages that failed to roll back
+	NotUndone map[Stage]error
+}
+
+func (e *SequenceError) Error() string {
+	msg := fmt.Sprintf("%v failed: %v", e.Stage, e.Err)
+	if len(e.Undone) > 0 {
+		msg += fmt.Sprintf(" (rolled back %v)", e.Undone)
+	}
+	for stage, err := range e.NotUndone {
+		msg += fmt.Sprintf(" (failed to roll back %v: %v)", stage, err)
+	}
+
+	return msg
+}
+
+func (e *SequenceError) Unwrap() error {
+	return e.Err
+}
+
+// runStages runs the stages in order. If one fails, the stages before it are undone in reverse order and a
+// *SequenceError is returned.
+func runStages(ctx context.Context, srv Server, stages []stage) error {
+	for n, s := range stages {
+		reportProgress(ctx, Progress{Server: srv, Stage: s.name})
+
+		err := s.run(ctx)
+		if err == nil {
+			continue
+		}
+		log.Printf("%v of %v failed: %v", s.name, srv.Name, err)
+
+		seqErr := &SequenceError{Stage: s.name, Err: err, NotUndone: make(map[Stage]error)}
+
+		// Roll back even if the operation was cancelled, so a shutdown doesn't leave the instance running
+		undoCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RollbackTimeout)
+		defer cancel()
+		rollbacks.Add(1)
+		defer rollbacks.Add(-1)
+
+		for i := n - 1; i >= 0; i-- {
+			if stages[i].undo == nil {
+				continue
+			}
+
+			log.Printf("Rolling back %v of %v", stages[i].name, srv.Name)
+			reportProgress(ctx, Progress{Server: srv, Stage: stages[i].name, RollingBack: true})
+
+			if err := stages[i].undo(undoCtx); err != nil {
+				log.Printf("Error rolling back %v of %v: %v", stages[i].name, srv.Name, err)
+				seqErr.NotUndone[stages[i].name] = err
+				continue
+			}
+			seqErr.Undone = append(seqErr.Undone, stages[i].name)
+		}
+
+		return seqErr
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package pixelmon
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultPort is the port Minecraft clients connect to if the address has none
+	defaultPort = 25565
+
+	// defaultTTL is the TTL of the DNS records in seconds
+	defaultTTL = 300
+)
+
+// Server is a Pixelmon server running on an EC2 instance, a Docker container or a local process
+type Server struct {
+	Name         string `json:"name"`
+	InstanceID   string `json:"instance_id"`
+	Region       string `json:"region"`
+	HostedZoneID string `json:"hosted_zone_id"`
+	Domain       string `json:"domain"`
+	Subdomain    string `json:"subdomain"`
+
+	// NameTag finds the EC2 instance by its Name tag instead of InstanceID, so the instance can be replaced
+	NameTag string `json:"name_tag,omitempty"`
+
+	// Port is the port of the Minecraft service, 25565 if not set
+	Port int `json:"port,omitempty"`
+
+	// SRV manages a _minecraft._tcp SRV record alongside the address records, so players don't need the port
+	SRV bool `json:"srv,omitempty"`
+
+	// TTL is the TTL of the DNS records in seconds, 300 if not set
+	TTL int64 `json:"ttl,omitempty"`
+
+	// DNSProvider manages the DNS records: route53, cloudflare or hosts. HostedZoneID is the zone of the provider,
+	// which hosts doesn't use since its file is PIXELMON_HOSTS_FILE. Route53 is used if not set.
+	DNSProvider string `json:"dns_provider,omitempty"`
+
+	// Backend runs the server: ec2, docker or process. InstanceID is the instance of the backend, which is the
+	// container for docker and the server's directory for process. EC2 is used if not set.
+	Backend string `json:"backend,omitempty"`
+
+	// Hibernate hibernates the EC2 instance on stop instead of stopping the service, so it resumes where it left off
+	Hibernate bool `json:"hibernate,omitempty"`
+
+	// PublicIP is the address the DNS records point to for docker and process, which have no public address of their
+	// own
+	PublicIP string `json:"public_ip,omitempty"`
+}
+
+// DefaultServer returns the server set by the PIXELMON_* environment variables
+func DefaultServer() Server {
+	return Server{
+		Name:         ServerName,
+		InstanceID:   os.Getenv("PIXELMON_INSTANCE_ID"),
+		Region:       os.Getenv("PIXELMON_REGION"),
+		HostedZoneID: os.Getenv("PIXELMON_HOSTED_ZONE_ID"),
+		Domain:       os.Getenv("PIXELMON_DOMAIN"),
+		Subdomain:    os.Getenv("PIXELMON_SUBDOMAIN"),
+		NameTag:      os.Getenv("PIXELMON_NAME"),
+		Port:         int(getInt("PIXELMON_PORT")),
+		SRV:          os.Getenv("PIXELMON_SRV") == "true",
+		TTL:          getInt("PIXELMON_TTL"),
+		DNSProvider:  os.Getenv("PIXELMON_DNS_PROVIDER"),
+		Backend:      os.Getenv("PIXELMON_BACKEND"),
+		PublicIP:     os.Getenv("PIXELMON_PUBLIC_IP"),
+		Hibernate:    os.Getenv("PIXELMON_HIBERNATE") == "true",
+	}
+}
+
+var (
+	servers     []Server
+	serversOnce sync.Once
+)
+
+// Servers returns the servers guilds can link, which are the servers in the JSON file at PIXELMON_SERVERS and the
+// server set by the PIXELMON_* environment variables if it has an instance. Guilds can only link these, since the bot
+// manages them with the operator's credentials.
+func Servers() []Server {
+	serversOnce.Do(func() {
+		if path := os.Getenv("PIXELMON_SERVERS"); path != "" {
+			data, err := os.ReadFile(path)
+			if err == nil {
+				err = json.Unmarshal(data, &servers)
+			}
+			if err != nil {
+				log.Printf("Error loading PIXELMON_SERVERS: %v", err)
+				servers = nil
+			}
+		}
+
+		if srv := DefaultServer(); srv.InstanceID != "" || srv.NameTag != "" {
+			servers = append(servers, srv)
+		}
+	})
+
+	return servers
+}
+
+// LookupServer finds the server guilds can link with the name
+func LookupServer(name string) (Server, bool) {
+	for _, srv := range Servers() {
+		if strings.EqualFold(srv.Name, name) {
+			return srv, true
+		}
+	}
+
+	return Server{}, false
+}
+
+// getInt reads a number from the environment variable, which is 0 if it isn't set or is invalid
+func getInt(key string) int64 {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return 0
+	}
+
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		log.Printf("Invalid %v %q: %v", key, value, err)
+		return 0
+	}
+
+	return n
+}
+
+// Hostname returns the DNS name of the server
+func (srv Server) Hostname() string {
+	return fmt.Sprintf("%v.%v", srv.Subdomain, srv.Domain)
+}
+
+// Address returns the address players connect to, which only has the port if it isn't the default and there is no
+// SRV record for it
+func (srv Server) Address() string {
+	if srv.SRV || srv.MinecraftPort() == defaultPort {
+		return srv.Hostname()
+	}
+
+	return net.JoinHostPort(srv.Hostname(), strconv.Itoa(srv.Port))
+}
+
+// MinecraftPort returns the port of the Minecraft service
+func (srv Server) MinecraftPort() int {
+	if srv.Port == 0 {
+		return defaultPort
+	}
+
+	return srv.Port
+}
+
+// SRVName returns the name of the server's SRV record
+func (srv Server) SRVName() string {
+	return "_minecraft._tcp." + srv.Hostname()
+}
+
+// RecordTTL returns the TTL of the server's DNS records in seconds
+func (srv Server) RecordTTL() int64 {
+	if srv.TTL <= 0 {
+		return defaultTTL
+	}
+
+	return srv.TTL
+}
+
+// Key identifies the server's instance, even if guilds link it under different names
+func (srv Server) Key() string {
+	if srv.NameTag != "" {
+		return srv.Region + "/Name=" + srv.NameTag
+	}
+
+	return srv.Region + "/" + srv.InstanceID
+}
@@ -0,0 +1,171 @@
+package pixelmon
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	logFile = "logs/latest.log"
+
+	// logChunk is the most of the log read at once, which keeps the output under the 24,000 characters EC2 commands
+	// return
+	logChunk = 16000
+
+	// readLogScript prints the offset the log is read from and the log from there. It is formatted with the offset and
+	// logChunk. A negative offset only prints the size of the log, and the log is read from its start if it is shorter
+	// than the offset, since the service replaced it.
+	readLogScript = `f=` + logFile + `
+if [ ! -f "$f" ]; then echo 0; exit 0; fi
+size=$(wc -c < "$f")
+offset=%d
+if [ "$offset" -lt 0 ]; then echo "$size"; exit 0; fi
+if [ "$size" -lt "$offset" ]; then offset=0; fi
+echo "$offset"
+tail -c +$((offset + 1)) "$f" | head -c %d`
+)
+
+// Kinds of spawns that are announced
+const (
+	SpawnLegendary = "legendary"
+	SpawnShiny     = "shiny"
+)
+
+// SpawnPatterns are the regular expressions that match Pixelmon's spawn broadcasts in the log by the kind of spawn, set
+// by PIXELMON_LEGENDARY_PATTERN and PIXELMON_SHINY_PATTERN. They are matched against the message of each log line and
+// name the Pokémon with a "pokemon" group and optionally the biome with a "biome" group. An empty pattern turns off
+// announcing that kind of spawn.
+var SpawnPatterns = map[string]string{
+	SpawnLegendary: getEnv("PIXELMON_LEGENDARY_PATTERN", `(?i)\blegendary (?:pok[eé]mon,? )?(?P<pokemon>[^,!]+?),? has (?:spawned|appeared)(?: (?:in|near|at) (?:an? |the )?(?P<biome>[^!]+?)(?: biome)?)?[!.]*$`),
+	SpawnShiny:     getEnv("PIXELMON_SHINY_PATTERN", `(?i)\bshiny (?:pok[eé]mon,? )?(?P<pokemon>[^,!]+?),? has (?:spawned|appeared)(?: (?:in|near|at) (?:an? |the )?(?P<biome>[^!]+?)(?: biome)?)?[!.]*$`),
+}
+
+// spawnKinds are the kinds of spawns in the order their patterns are tried
+var spawnKinds = []string{SpawnLegendary, SpawnShiny}
+
+// logPrefix matches the time, thread and logger before the message of a log line, like
+// "[19Oct2026 12:34:56.789] [Server thread/INFO] [net.minecraft.server.dedicated.DedicatedServer/]: "
+var logPrefix = regexp.MustCompile(`^(?:\[[^\]]*\] ?)+: `)
+
+// chatMessage matches messages players sent, which could otherwise look like spawn broadcasts
+var chatMessage = regexp.MustCompile(`^(?:\[Not Secure\] )?<[^>]+> `)
+
+// Spawn is a spawn announced in the log
+type Spawn struct {
+	Kind    string
+	Pokemon string
+	Biome   string
+}
+
+// SpawnParser finds spawns in log lines
+type SpawnParser struct {
+	kinds    []string
+	patterns []*regexp.Regexp
+}
+
+// NewSpawnParser compiles the patterns, which are keyed by the kind of spawn
+func NewSpawnParser(patterns map[string]string) (*SpawnParser, error) {
+	kinds := append([]string{}, spawnKinds...)
+	for kind := range patterns {
+		if kind != SpawnLegendary && kind != SpawnShiny {
+			kinds = append(kinds, kind)
+		}
+	}
+
+	p := &SpawnParser{}
+	for _, kind := range kinds {
+		pattern := patterns[kind]
+		if pattern == "" {
+			continue
+		}
+
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v spawn pattern: %w", kind, err)
+		}
+		if re.SubexpIndex("pokemon") < 0 {
+			return nil, fmt.Errorf("%v spawn pattern has no pokemon group", kind)
+		}
+
+		p.kinds = append(p.kinds, kind)
+		p.patterns = append(p.patterns, re)
+	}
+
+	return p, nil
+}
+
+// Enabled checks if the parser has any patterns
+func (p *SpawnParser) Enabled() bool {
+	return len(p.patterns) > 0
+}
+
+// Parse returns the spawn announced in the log line, if there is one
+func (p *SpawnParser) Parse(line string) (Spawn, bool) {
+	message := strings.TrimSpace(formatCodes.ReplaceAllString(line, ""))
+	message = logPrefix.ReplaceAllString(message, "")
+	if chatMessage.MatchString(message) {
+		return Spawn{}, false
+	}
+
+	for n, re := range p.patterns {
+		match := re.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+
+		spawn := Spawn{
+			Kind:    p.kinds[n],
+			Pokemon: strings.TrimSpace(match[re.SubexpIndex("pokemon")]),
+		}
+		if index := re.SubexpIndex("biome"); index >= 0 {
+			spawn.Biome = strings.TrimSpace(match[index])
+		}
+		if spawn.Pokemon == "" {
+			continue
+		}
+
+		return spawn, true
+	}
+
+	return Spawn{}, false
+}
+
+// ReadLog returns the complete lines added to the server's log since the offset and the offset to read from next. A
+// negative offset starts at the end of the log. The instance must be running.
+func ReadLog(ctx context.Context, srv Server, offset int64) ([]string, int64, error) {
+	b, err := runningBackend(ctx, srv)
+	if err != nil {
+		return nil, offset, err
+	}
+
+	output, err := b.shell(ctx, fmt.Sprintf(readLogScript, offset, logChunk))
+	if err != nil {
+		return nil, offset, err
+	}
+
+	first, data, _ := strings.Cut(output, "\n")
+	start, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return nil, offset, fmt.Errorf("invalid log offset %q", first)
+	}
+	if offset < 0 {
+		return nil, start, nil
+	}
+
+	// The last line may still be being written, so it is read again next time
+	end := strings.LastIndexByte(data, '\n')
+	if end < 0 {
+		// A line longer than a chunk can never be read whole, so it is skipped
+		if len(data) >= logChunk {
+			return nil, start + int64(len(data)), nil
+		}
+		return nil, start, nil
+	}
+
+	lines := strings.Split(strings.ReplaceAll(data[:end], "\r", ""), "\n")
+
+	return lines, start + int64(end) + 1, nil
+}
@@ -0,0 +1,133 @@
+package pixelmon
+
+import "testing"
+
+func TestSpawnParserParse(t *testing.T) {
+	p, err := NewSpawnParser(SpawnPatterns)
+	if err != nil {
+		t.Fatalf("NewSpawnParser() error = %v", err)
+	}
+	if !p.Enabled() {
+		t.Fatal("Enabled() = false with the default patterns")
+	}
+
+	prefix := "[19Oct2026 12:34:56.789] [Server thread/INFO] [net.minecraft.server.dedicated.DedicatedServer/]: "
+
+	tests := []struct {
+		name string
+		line string
+		want Spawn
+		ok   bool
+	}{
+		{
+			name: "legendary with biome",
+			line: prefix + "A legendary Mewtwo has spawned in a Mountains biome!",
+			want: Spawn{Kind: SpawnLegendary, Pokemon: "Mewtwo", Biome: "Mountains"},
+			ok:   true,
+		},
+		{
+			name: "legendary without biome",
+			line: prefix + "A legendary Pokémon, Lugia, has appeared!",
+			want: Spawn{Kind: SpawnLegendary, Pokemon: "Lugia"},
+			ok:   true,
+		},
+		{
+			name: "shiny with biome",
+			line: prefix + "A shiny Magikarp has spawned near the Ocean!",
+			want: Spawn{Kind: SpawnShiny, Pokemon: "Magikarp", Biome: "Ocean"},
+			ok:   true,
+		},
+		{
+			name: "shiny without biome",
+			line: prefix + "A Shiny Pokemon Eevee has spawned.",
+			want: Spawn{Kind: SpawnShiny, Pokemon: "Eevee"},
+			ok:   true,
+		},
+		{
+			name: "format codes",
+			line: prefix + "§5§lA legendary §dSuicune §5has spawned in a §aTaiga§5 biome!§r",
+			want: Spawn{Kind: SpawnLegendary, Pokemon: "Suicune", Biome: "Taiga"},
+			ok:   true,
+		},
+		{
+			name: "without log prefix",
+			line: "A legendary Ho-Oh has spawned!",
+			want: Spawn{Kind: SpawnLegendary, Pokemon: "Ho-Oh"},
+			ok:   true,
+		},
+		{
+			name: "chat",
+			line: prefix + "<Ash> A legendary Mew has spawned in a Jungle biome!",
+		},
+		{
+			name: "unsigned chat",
+			line: prefix + "[Not Secure] <Ash> A shiny Pikachu has spawned!",
+		},
+		{
+			name: "chat with format codes",
+			line: prefix + "§f<Ash> §fA legendary Mew has spawned!",
+		},
+		{
+			name: "other message",
+			line: prefix + "Ash joined the game",
+		},
+		{
+			name: "empty",
+			line: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := p.Parse(tt.line)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Parse(%q) = %+v, %v, want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNewSpawnParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns map[string]string
+		enabled  bool
+		wantErr  bool
+	}{
+		{name: "defaults", patterns: SpawnPatterns, enabled: true},
+		{name: "turned off", patterns: map[string]string{SpawnLegendary: "", SpawnShiny: ""}},
+		{name: "custom kind", patterns: map[string]string{"ultra beast": `(?P<pokemon>\w+) came through an ultra wormhole`}, enabled: true},
+		{name: "invalid pattern", patterns: map[string]string{SpawnLegendary: `(?P<pokemon>\w+`}, wantErr: true},
+		{name: "no pokemon group", patterns: map[string]string{SpawnShiny: `shiny (\w+) has spawned`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewSpawnParser(tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSpawnParser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && p.Enabled() != tt.enabled {
+				t.Errorf("Enabled() = %v, want %v", p.Enabled(), tt.enabled)
+			}
+		})
+	}
+}
+
+func TestSpawnParserCustomKind(t *testing.T) {
+	p, err := NewSpawnParser(map[string]string{
+		SpawnLegendary: "",
+		"ultra beast":  `(?P<pokemon>\w+) came through an ultra wormhole`,
+	})
+	if err != nil {
+		t.Fatalf("NewSpawnParser() error = %v", err)
+	}
+
+	want := Spawn{Kind: "ultra beast", Pokemon: "Nihilego"}
+	if got, ok := p.Parse("Nihilego came through an ultra wormhole"); !ok || got != want {
+		t.Errorf("Parse() = %+v, %v, want %+v", got, ok, want)
+	}
+	if _, ok := p.Parse("A legendary Mewtwo has spawned!"); ok {
+		t.Error("Parse() found a legendary spawn with the legendary pattern turned off")
+	}
+}
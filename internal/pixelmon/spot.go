@@ -0,0 +1,136 @@
+package pixelmon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kn-lim/seigetsu-bot/internal/catalog"
+)
+
+const (
+	// instanceActionCommand prints the spot interruption notice from the instance metadata, or nothing if there isn't
+	// one
+	instanceActionCommand = `TOKEN=$(curl -s -X PUT http://169.254.169.254/latest/api/token -H "X-aws-ec2-metadata-token-ttl-seconds: 60") && ` +
+		`curl -s -f -H "X-aws-ec2-metadata-token: $TOKEN" http://169.254.169.254/latest/meta-data/spot/instance-action || true`
+
+	defaultBackupCommand = "cd " + serverDir + " && mkdir -p backups && tar -czf backups/world-$(date +%Y%m%d%H%M%S).tar.gz world"
+)
+
+// backupCommand is the shell command that backs up the world before a spot instance is interrupted
+var backupCommand = getEnv("PIXELMON_BACKUP_COMMAND", defaultBackupCommand)
+
+// SpotInterruption is a notice that AWS is about to reclaim a spot instance
+type SpotInterruption struct {
+	// Action is what happens to the instance: stop, terminate or hibernate
+	Action string    `json:"action"`
+	Time   time.Time `json:"time"`
+}
+
+// GetSpotInterruption returns the interruption notice of the server's spot instance, or nil if the server isn't a
+// running spot instance or there is no notice
+func GetSpotInterruption(ctx context.Context, srv Server) (*SpotInterruption, error) {
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		return nil, err
+	}
+	eb, ok := b.(*ec2Backend)
+	if !ok {
+		return nil, nil
+	}
+
+	instance, err := eb.describe(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !instance.Spot || instance.State != instanceRunning {
+		return nil, nil
+	}
+
+	output, err := runCommand(ctx, eb.cfg, eb.srv, instanceActionCommand)
+	if err != nil {
+		return nil, err
+	}
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return nil, nil
+	}
+
+	var notice SpotInterruption
+	if err := json.Unmarshal([]byte(output), &notice); err != nil {
+		return nil, fmt.Errorf("error parsing spot interruption notice: %w", err)
+	}
+
+	return &notice, nil
+}
+
+// HandleSpotInterruption uses the two minutes before a spot instance is interrupted to warn players, save the world
+// and back it up, then waits for the instance to go away
+func HandleSpotInterruption(ctx context.Context, srv Server, notice SpotInterruption) (err error) {
+	log.Printf("%v spot instance will %v at %v", srv.Name, notice.Action, notice.Time)
+
+	// Let listeners know the outcome is unknown if anything fails
+	defer func() {
+		if err != nil {
+			setState(srv, StateUnknown)
+		}
+	}()
+
+	setState(srv, StateStopping)
+
+	b, err := getBackend(ctx, srv)
+	if err != nil {
+		return err
+	}
+	eb, ok := b.(*ec2Backend)
+	if !ok {
+		return ErrNotSupported
+	}
+
+	// Warn players
+	messages := catalog.Default()
+	remaining := time.Until(notice.Time).Round(time.Second)
+	if remaining < 0 {
+		remaining = 0
+	}
+	text := messages.Get(catalog.DefaultLocale, "ingame.spot_interruption", map[string]any{"Time": messages.Duration(catalog.DefaultLocale, remaining)})
+	if err := b.console(ctx, "say "+text); err != nil {
+		return err
+	}
+
+	// Delete Pixelmon DNS Entry
+	if err := deletePixelmonDNSEntry(ctx, eb.cfg, eb.srv); err != nil {
+		return err
+	}
+
+	// Save and back up the world
+	if _, err := runCommand(ctx, eb.cfg, eb.srv, rcon("save-all flush"), backupCommand); err != nil {
+		return fmt.Errorf("failed to back up pixelmon: %w", err)
+	}
+	log.Printf("%v backed up the world before the spot interruption", srv.Name)
+
+	// Wait till AWS stops or terminates the instance
+	if err := waitFor(ctx, srv, PhaseInstanceStopped, func(ctx context.Context) (bool, error) {
+		instance, err := eb.describe(ctx)
+		if errors.Is(err, ErrNotFound) || isInstanceGone(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		return instance.State == instanceStopped || instance.State == string(ec2Types.InstanceStateNameTerminated), nil
+	}); err != nil {
+		return err
+	}
+
+	log.Printf("%v spot instance was interrupted", srv.Name)
+	setState(srv, StateOffline)
+
+	return nil
+}
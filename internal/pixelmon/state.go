@@ -0,0 +1,41 @@
+package pixelmon
+
+import "sync"
+
+// State is the lifecycle state of the Pixelmon server as changed by the bot's own operations
+type State int
+
+const (
+	// StateUnknown means an operation ended without a known outcome and the server should be checked again
+	StateUnknown State = iota
+	StateOffline
+	StateStarting
+	StateOnline
+	StateStopping
+	StateRestarting
+)
+
+var (
+	stateListeners   []func(Server, State)
+	stateListenersMu sync.Mutex
+)
+
+// OnStateChange registers a function that is called whenever a lifecycle operation changes the state of a server
+func OnStateChange(f func(Server, State)) {
+	stateListenersMu.Lock()
+	defer stateListenersMu.Unlock()
+
+	stateListeners = append(stateListeners, f)
+}
+
+// setState notifies every listener of the server's new state
+func setState(srv Server, state State) {
+	stateListenersMu.Lock()
+	listeners := make([]func(Server, State), len(stateListeners))
+	copy(listeners, stateListeners)
+	stateListenersMu.Unlock()
+
+	for _, f := range listeners {
+		f(srv, state)
+	}
+}
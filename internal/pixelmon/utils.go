@@ -2,170 +2,168 @@ package pixelmon
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2Types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
-	"github.com/aws/aws-sdk-go-v2/service/route53"
-	route53Types "github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmTypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 )
 
 const (
-	Online = iota
-	Offline
-	Not_Found
-	Err_Status
-	Starting
-	Err_Start
-	Stopping
-	Err_Stop
-	Whitelist
-	Success_Whitelist
-	Err_Whitelist
-	NumPlayers
-	Err_NumPlayers
-	SendingMessage
-	Success_SendingMessage
-	Err_SendingMessage
+	statusURL     = "https://api.mcstatus.io/v2/status/java/pixelmon.knlim.dev"
+	delay         = 30
+	serverDir     = "/opt/pixelmon"
+	startCommand  = "cd " + serverDir + "/ && tmux new-session -d -s minecraft './start.sh'"
+	whitelistFile = "whitelist.json"
+
+	// commandPollInterval is how often runCommand checks if the commands finished
+	commandPollInterval = 500 * time.Millisecond
+	commandTimeout      = 30 * time.Second
 )
 
-const (
-	statusURL            = "https://api.mcstatus.io/v2/status/java/pixelmon.knlim.dev"
-	delay                = 30
-	MinecraftersRoleName = "Minecrafters"
-)
+// ServerName is the name of the server used in messages
+const ServerName = "Pixelmon"
 
-var (
-	RequiredRoleNames = []string{
-		MinecraftersRoleName,
-	}
+// restartWarnings are the in-game warnings broadcast before restarting, in order
+var restartWarnings = []struct {
+	remaining time.Duration
+	wait      time.Duration
+}{
+	{1 * time.Minute, 50 * time.Second},
+	{10 * time.Second, 10 * time.Second},
+}
 
-	Message = []string{
-		":green_circle:   Pixelmon is ONLINE",
-		":red_circle:   Pixelmon is OFFLINE",
-		":grey_exclamation:   No Pixelmon server was found",
-		":exclamation:   Error checking Pixelmon's status",
-		":green_square:   Starting the Pixelmon server",
-		":exclamation:  Failed to start the Pixelmon server",
-		":red_square:   Stopping the Pixelmon server",
-		":exclamation:   Failed to stop the Pixelmon server",
-		":green_square:   Sending command to whitelist ",
-		":green_circle:   Successfully sent command to whitelist ",
-		":exclamation:   Error sending command to whitelist ",
-		":green_circle:   Current Number of Players: ",
-		":exclamation:   Error getting number of players",
-		":green_square:   Sending command to say ",
-		":green_circle:   Successfully sent command to say ",
-		":exclamation:   Error sending command to say ",
-	}
+var (
+	ErrOnline   = errors.New("pixelmon is online")
+	ErrOffline  = errors.New("pixelmon is offline")
+	ErrNotFound = errors.New("no pixelmon server was found")
 )
 
-func getConfig() (aws.Config, error) {
-	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion(os.Getenv("PIXELMON_REGION")))
-	if err != nil {
-		log.Printf("Error creating AWS config: %v", err)
-		return aws.Config{}, errors.New("error creating AWS config")
-	}
+// rcon returns the shell command to run a Minecraft command through RCON
+func rcon(command string) string {
+	return "mcrcon -H localhost -p " + os.Getenv("RCON_PASSWORD") + " \"" + command + "\""
+}
 
-	return cfg, nil
+// writeFileCommand returns the shell command to replace the contents of the file. The contents are encoded so they
+// don't need quoting.
+func writeFileCommand(name string, contents string) string {
+	return "echo " + base64.StdEncoding.EncodeToString([]byte(contents)) + " | base64 -d > " + name
 }
 
-func getPixelmonServer(cfg aws.Config) (*ec2.Client, ec2Types.Instance, error) {
-	client := ec2.NewFromConfig(cfg)
+// withoutRetries turns off the SDK's retries for a call to SSM, so a command isn't run again when a response is lost.
+// sendValue retries the calls that weren't sent instead.
+func withoutRetries(o *ssm.Options) {
+	o.Retryer = aws.NopRetryer{}
+}
 
-	input := &ec2.DescribeInstancesInput{
-		InstanceIds: []string{
-			os.Getenv("PIXELMON_INSTANCE_ID"),
-		},
+// sendCommand runs shell commands on the server's EC2 instance
+func sendCommand(ctx context.Context, cfg aws.Config, srv Server, commands ...string) error {
+	client := ssm.NewFromConfig(cfg)
+	documentName := "AWS-RunShellScript"
+	params := map[string][]string{
+		"commands": commands,
 	}
+	input := &ssm.SendCommandInput{
+		InstanceIds:  []string{srv.InstanceID},
+		DocumentName: &documentName,
+		Parameters:   params,
+	}
+	_, err := sendValue(ctx, srv, StepSendCommand, func(ctx context.Context) (*ssm.SendCommandOutput, error) {
+		return client.SendCommand(ctx, input, withoutRetries)
+	})
+	return err
+}
 
-	result, err := client.DescribeInstances(context.TODO(), input)
+// runCommand runs shell commands on the server's EC2 instance and waits for their output
+func runCommand(ctx context.Context, cfg aws.Config, srv Server, commands ...string) (string, error) {
+	client := ssm.NewFromConfig(cfg)
+	documentName := "AWS-RunShellScript"
+	input := &ssm.SendCommandInput{
+		InstanceIds:  []string{srv.InstanceID},
+		DocumentName: &documentName,
+		Parameters: map[string][]string{
+			"commands": commands,
+		},
+	}
+	output, err := sendValue(ctx, srv, StepSendCommand, func(ctx context.Context) (*ssm.SendCommandOutput, error) {
+		return client.SendCommand(ctx, input, withoutRetries)
+	})
 	if err != nil {
-		return nil, ec2Types.Instance{}, errors.New(Message[Err_Status])
+		return "", err
 	}
 
-	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
-		return nil, ec2Types.Instance{}, errors.New(Message[Not_Found])
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	for {
+		if err := sleep(ctx, commandPollInterval); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return "", fmt.Errorf("command timed out after %v", commandTimeout)
+			}
+			return "", err
+		}
+
+		invocation, err := callValue(ctx, srv, StepCommandOutput, func(ctx context.Context) (*ssm.GetCommandInvocationOutput, error) {
+			return client.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+				CommandId:  output.Command.CommandId,
+				InstanceId: &srv.InstanceID,
+			})
+		})
+		if err != nil {
+			// The invocation doesn't exist until the instance picks up the command
+			var notFound *ssmTypes.InvocationDoesNotExist
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return "", err
+		}
+
+		switch invocation.Status {
+		case ssmTypes.CommandInvocationStatusPending, ssmTypes.CommandInvocationStatusInProgress, ssmTypes.CommandInvocationStatusDelayed:
+			continue
+		case ssmTypes.CommandInvocationStatusSuccess:
+			return aws.ToString(invocation.StandardOutputContent), nil
+		default:
+			return "", fmt.Errorf("command %v: %v", invocation.Status, aws.ToString(invocation.StandardErrorContent))
+		}
 	}
-
-	return client, result.Reservations[0].Instances[0], nil
 }
 
-func createPixelmonDNSEntry(cfg aws.Config, instance ec2Types.Instance, zoneID string, domain string, subdomain string) error {
-	publicIP := instance.PublicIpAddress
-
-	client := route53.NewFromConfig(cfg)
-
-	fqdn := fmt.Sprintf("%s.%s", subdomain, domain)
-	log.Printf("Creating A record of %v to %v", *publicIP, fqdn)
-
-	_, err := client.ChangeResourceRecordSets(context.TODO(), &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: &zoneID,
-		ChangeBatch: &route53Types.ChangeBatch{
-			Changes: []route53Types.Change{
-				{
-					Action: route53Types.ChangeActionUpsert,
-					ResourceRecordSet: &route53Types.ResourceRecordSet{
-						Name: &fqdn,
-						Type: route53Types.RRTypeA,
-						TTL:  aws.Int64(300),
-						ResourceRecords: []route53Types.ResourceRecord{
-							{
-								Value: publicIP,
-							},
-						},
-					},
-				},
-			},
-		},
-	})
+func getConfig(ctx context.Context, srv Server) (aws.Config, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(srv.Region))
 	if err != nil {
-		return fmt.Errorf("failed to create A record: %v", err)
+		log.Printf("Error creating AWS config: %v", err)
+		return aws.Config{}, errors.New("error creating AWS config")
 	}
 
-	log.Printf("Created A record of %v to %v.%v", *publicIP, subdomain, domain)
-
-	return nil
+	return cfg, nil
 }
 
-func deletePixelmonDNSEntry(cfg aws.Config, instance ec2Types.Instance, zoneID string, domain string, subdomain string) error {
-	publicIP := instance.PublicIpAddress
-
-	client := route53.NewFromConfig(cfg)
-
-	fqdn := fmt.Sprintf("%s.%s", subdomain, domain)
-	log.Printf("Deleting A record of %v to %v", *publicIP, fqdn)
-
-	_, err := client.ChangeResourceRecordSets(context.TODO(), &route53.ChangeResourceRecordSetsInput{
-		HostedZoneId: &zoneID,
-		ChangeBatch: &route53Types.ChangeBatch{
-			Changes: []route53Types.Change{
-				{
-					Action: route53Types.ChangeActionDelete,
-					ResourceRecordSet: &route53Types.ResourceRecordSet{
-						Name: &fqdn,
-						Type: route53Types.RRTypeA,
-						TTL:  aws.Int64(300),
-						ResourceRecords: []route53Types.ResourceRecord{
-							{
-								Value: publicIP,
-							},
-						},
-					},
-				},
-			},
+func getPixelmonServer(ctx context.Context, client *ec2.Client, srv Server) (ec2Types.Instance, error) {
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: []string{
+			srv.InstanceID,
 		},
+	}
+
+	result, err := callValue(ctx, srv, StepDescribeInstance, func(ctx context.Context) (*ec2.DescribeInstancesOutput, error) {
+		return client.DescribeInstances(ctx, input)
 	})
 	if err != nil {
-		return fmt.Errorf("failed to create A record: %v", err)
+		return ec2Types.Instance{}, fmt.Errorf("error describing instance: %w", err)
 	}
 
-	log.Printf("Deleted A record of %v to %v.%v", *publicIP, subdomain, domain)
+	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+		return ec2Types.Instance{}, ErrNotFound
+	}
 
-	return nil
+	return result.Reservations[0].Instances[0], nil
 }
@@ -0,0 +1,104 @@
+package pixelmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+const (
+	// minPollInterval is how long to wait before checking again the first time. It doubles after every check up to
+	// maxPollInterval.
+	minPollInterval = 5 * time.Second
+	maxPollInterval = delay * time.Second
+)
+
+// Phase is a part of a lifecycle operation that waits for the server to reach a state
+type Phase string
+
+const (
+	PhaseInstanceRunning Phase = "instance running"
+	PhaseInstanceStopped Phase = "instance stopped"
+	PhaseServiceOnline   Phase = "service online"
+	PhaseServiceOffline  Phase = "service offline"
+	PhaseDNSInSync       Phase = "DNS change in sync"
+	PhaseDNSResolved     Phase = "DNS resolved"
+)
+
+// Timeouts are how long each phase waits before giving up. They are set from PIXELMON_INSTANCE_TIMEOUT,
+// PIXELMON_START_TIMEOUT, PIXELMON_STOP_TIMEOUT and PIXELMON_DNS_TIMEOUT, which take durations like "10m".
+var Timeouts = map[Phase]time.Duration{
+	PhaseInstanceRunning: getTimeout("PIXELMON_INSTANCE_TIMEOUT", 5*time.Minute),
+	PhaseInstanceStopped: getTimeout("PIXELMON_INSTANCE_TIMEOUT", 5*time.Minute),
+	PhaseServiceOnline:   getTimeout("PIXELMON_START_TIMEOUT", 15*time.Minute),
+	PhaseServiceOffline:  getTimeout("PIXELMON_STOP_TIMEOUT", 5*time.Minute),
+	PhaseDNSInSync:       getTimeout("PIXELMON_DNS_TIMEOUT", 5*time.Minute),
+	PhaseDNSResolved:     getTimeout("PIXELMON_DNS_TIMEOUT", 5*time.Minute),
+}
+
+var ErrTimeout = errors.New("timed out")
+
+// getTimeout reads a duration from the environment variable, falling back to def if it isn't set or is invalid
+func getTimeout(key string, def time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return def
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		log.Printf("Invalid %v %q, using %v", key, value, def)
+		return def
+	}
+
+	return timeout
+}
+
+// waitFor calls check until it returns true, backing off between calls. It stops with ErrTimeout when the phase
+// takes longer than its timeout, or with the context's error when ctx is done.
+func waitFor(ctx context.Context, srv Server, phase Phase, check func(ctx context.Context) (bool, error)) error {
+	timeout := Timeouts[phase]
+	phaseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := minPollInterval
+	for {
+		done, err := check(phaseCtx)
+		if err != nil && phaseCtx.Err() == nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		if err == nil {
+			log.Printf("Waiting for %v %v...", srv.Name, phase)
+			err = sleep(phaseCtx, interval)
+		}
+		if err != nil {
+			// The phase's own deadline passed but the operation wasn't cancelled
+			if ctx.Err() == nil {
+				return fmt.Errorf("%w after %v waiting for %v %v", ErrTimeout, timeout, srv.Name, phase)
+			}
+			return ctx.Err()
+		}
+
+		interval = min(interval*2, maxPollInterval)
+	}
+}
+
+// sleep waits for d, returning early with the context's error when ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
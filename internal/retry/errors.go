@@ -0,0 +1,76 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// retryable is implemented by errors that know whether they are worth retrying, like HTTP status errors
+type retryable interface {
+	Retryable() bool
+}
+
+// awsRetryables are the errors the AWS SDK retries itself, like throttling, 5xx responses and connection errors
+var awsRetryables = awsretry.IsErrorRetryables(append([]awsretry.IsErrorRetryable{}, awsretry.DefaultRetryables...))
+
+// awsThrottles are the errors AWS returns when it throttled a request instead of handling it
+var awsThrottles = awsretry.IsErrorThrottles(append([]awsretry.IsErrorThrottle{}, awsretry.DefaultThrottles...))
+
+// IsRetryable reports whether a call that failed with err might succeed if it is made again
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// Let errors decide for themselves first, so a permanent HTTP status isn't retried because it looks temporary
+	var r retryable
+	if errors.As(err, &r) {
+		return r.Retryable()
+	}
+
+	switch awsRetryables.IsErrorRetryable(err) {
+	case aws.TrueTernary:
+		return true
+	case aws.FalseTernary:
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// IsUnsent reports whether a call that failed with err certainly wasn't acted on, because the connection was never made
+// or the request was throttled. Only these errors are safe to retry for calls that shouldn't run twice.
+func IsUnsent(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if awsThrottles.IsErrorThrottle(err) == aws.TrueTernary {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
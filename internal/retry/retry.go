@@ -0,0 +1,112 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Policy is how often and how long to retry a call that failed with a retryable error
+type Policy struct {
+	// Attempts is the most times the call is made, including the first
+	Attempts int
+
+	// Initial is the wait before the first retry. It is multiplied by Multiplier after every retry up to Max.
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+
+	// Jitter is the fraction of the wait that is randomized, so calls failing together don't retry together
+	Jitter float64
+
+	// Retryable decides which errors are retried, IsRetryable if not set
+	Retryable func(err error) bool
+}
+
+// Default retries for about 15 seconds, which rides out throttling and blips without hiding outages
+var Default = Policy{
+	Attempts:   5,
+	Initial:    500 * time.Millisecond,
+	Max:        8 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.5,
+}
+
+// Attempt is a failed call that is going to be retried
+type Attempt struct {
+	// Number is the attempt that failed, starting at 1
+	Number int
+	Err    error
+	Wait   time.Duration
+}
+
+// Error is the error of the last attempt of a call that didn't succeed
+type Error struct {
+	Attempts int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	if e.Attempts == 1 {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf("%v (after %v attempts)", e.Err, e.Attempts)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Do calls f until it succeeds, fails with an error that isn't retryable or runs out of attempts. onRetry, if set, is
+// called before waiting to retry. The error is an *Error unless ctx was done.
+func Do(ctx context.Context, p Policy, f func(ctx context.Context) error, onRetry func(Attempt)) error {
+	wait := p.Initial
+	for n := 1; ; n++ {
+		err := f(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if n >= p.Attempts || !p.isRetryable(err) {
+			return &Error{Attempts: n, Err: err}
+		}
+
+		jittered := p.jitter(wait)
+		if onRetry != nil {
+			onRetry(Attempt{Number: n, Err: err, Wait: jittered})
+		}
+
+		timer := time.NewTimer(jittered)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		wait = min(time.Duration(float64(wait)*p.Multiplier), p.Max)
+	}
+}
+
+// isRetryable checks if the policy retries the error
+func (p Policy) isRetryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryable(err)
+}
+
+// jitter randomizes the Jitter fraction of d
+func (p Policy) jitter(d time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return d
+	}
+
+	spread := float64(d) * p.Jitter
+	return time.Duration(float64(d) - spread + rand.Float64()*spread*2)
+}
@@ -0,0 +1,217 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// apiError is an AWS API error with a code
+type apiError struct {
+	code string
+}
+
+func (e apiError) Error() string     { return e.code }
+func (e apiError) ErrorCode() string { return e.code }
+
+// statusError decides whether it is retryable, like an HTTP status error
+type statusError struct {
+	retryable bool
+}
+
+func (e statusError) Error() string   { return fmt.Sprintf("retryable: %v", e.retryable) }
+func (e statusError) Retryable() bool { return e.retryable }
+
+// timeoutError is a network timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "deadline", err: context.DeadlineExceeded, want: true},
+		{name: "throttled", err: apiError{code: "ThrottlingException"}, want: true},
+		{name: "invalid instance", err: apiError{code: "InvalidInstanceID.NotFound"}, want: false},
+		{name: "retryable status", err: statusError{retryable: true}, want: true},
+		{name: "permanent status", err: statusError{retryable: false}, want: false},
+		{name: "wrapped permanent status", err: fmt.Errorf("describing: %w", statusError{retryable: false}), want: false},
+		{name: "timeout", err: timeoutError{}, want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "connection reset", err: syscall.ECONNRESET, want: true},
+		{name: "connection refused", err: syscall.ECONNREFUSED, want: true},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsUnsent(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "throttled", err: apiError{code: "ThrottlingException"}, want: true},
+		{name: "wrapped throttled", err: fmt.Errorf("send command: %w", apiError{code: "Throttling"}), want: true},
+		{name: "dial", err: &net.OpError{Op: "dial", Err: errors.New("no route to host")}, want: true},
+		{name: "dns", err: &net.DNSError{Err: "no such host", Name: "ssm.example.com"}, want: true},
+		{name: "connection refused", err: syscall.ECONNREFUSED, want: true},
+		{name: "read", err: &net.OpError{Op: "read", Err: syscall.ECONNRESET}, want: false},
+		{name: "timeout", err: timeoutError{}, want: false},
+		{name: "deadline", err: context.DeadlineExceeded, want: false},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: false},
+		{name: "server error", err: apiError{code: "InternalServerError"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUnsent(tt.err); got != tt.want {
+				t.Errorf("IsUnsent(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	policy := Policy{Attempts: 4, Initial: time.Millisecond, Max: 3 * time.Millisecond, Multiplier: 2}
+	retryable := syscall.ECONNRESET
+	permanent := errors.New("permanent")
+
+	tests := []struct {
+		name      string
+		policy    Policy
+		errs      []error
+		wantCalls int
+		wantErr   error
+		wantWaits []time.Duration
+	}{
+		{name: "success", policy: policy, errs: []error{nil}, wantCalls: 1},
+		{
+			name:      "success after retries",
+			policy:    policy,
+			errs:      []error{retryable, retryable, nil},
+			wantCalls: 3,
+			wantWaits: []time.Duration{time.Millisecond, 2 * time.Millisecond},
+		},
+		{
+			name:      "permanent error",
+			policy:    policy,
+			errs:      []error{retryable, permanent},
+			wantCalls: 2,
+			wantErr:   permanent,
+			wantWaits: []time.Duration{time.Millisecond},
+		},
+		{
+			name:      "out of attempts",
+			policy:    policy,
+			errs:      []error{retryable, retryable, retryable, retryable, nil},
+			wantCalls: 4,
+			wantErr:   retryable,
+			wantWaits: []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond},
+		},
+		{
+			name:      "custom retryable",
+			policy:    Policy{Attempts: 4, Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 2, Retryable: IsUnsent},
+			errs:      []error{syscall.ECONNREFUSED, retryable},
+			wantCalls: 2,
+			wantErr:   retryable,
+			wantWaits: []time.Duration{time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			var waits []time.Duration
+			err := Do(context.Background(), tt.policy, func(ctx context.Context) error {
+				calls++
+				return tt.errs[calls-1]
+			}, func(attempt Attempt) {
+				if attempt.Number != len(waits)+1 {
+					t.Errorf("attempt %v, want %v", attempt.Number, len(waits)+1)
+				}
+				waits = append(waits, attempt.Wait)
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("made %v calls, want %v", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			var retryErr *Error
+			if err != nil && (!errors.As(err, &retryErr) || retryErr.Attempts != tt.wantCalls) {
+				t.Errorf("Do() error = %#v, want an *Error after %v attempts", err, tt.wantCalls)
+			}
+			if fmt.Sprint(waits) != fmt.Sprint(tt.wantWaits) {
+				t.Errorf("waits = %v, want %v", waits, tt.wantWaits)
+			}
+		})
+	}
+}
+
+func TestDoCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := Do(ctx, Policy{Attempts: 5, Initial: time.Hour, Max: time.Hour, Multiplier: 1}, func(ctx context.Context) error {
+		calls++
+		return syscall.ECONNRESET
+	}, func(Attempt) {
+		cancel()
+	})
+
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("Do() = %v after %v calls, want context.Canceled after 1 call", err, calls)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		err  *Error
+		want string
+	}{
+		{err: &Error{Attempts: 1, Err: errors.New("boom")}, want: "boom"},
+		{err: &Error{Attempts: 3, Err: errors.New("boom")}, want: "boom (after 3 attempts)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	p := Policy{Jitter: 0.5}
+	for n := 0; n < 100; n++ {
+		if got := p.jitter(time.Second); got < 500*time.Millisecond || got > 1500*time.Millisecond {
+			t.Fatalf("jitter(1s) = %v, want between 500ms and 1.5s", got)
+		}
+	}
+
+	if got := (Policy{}).jitter(time.Second); got != time.Second {
+		t.Errorf("jitter(1s) without jitter = %v, want 1s", got)
+	}
+}
@@ -0,0 +1,145 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Collection stores values of type T as JSON in a bucket
+type Collection[T any] struct {
+	s      *Store
+	bucket string
+}
+
+// NewCollection returns the collection of values in the bucket
+func NewCollection[T any](s *Store, bucket string) Collection[T] {
+	return Collection[T]{s: s, bucket: bucket}
+}
+
+// Get reads the value of the key. It is false if the key doesn't exist.
+func (c Collection[T]) Get(key string) (T, bool, error) {
+	var value T
+	found := false
+
+	if c.s == nil {
+		return value, false, ErrClosed
+	}
+
+	err := c.s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(c.bucket))
+		if b == nil {
+			return nil
+		}
+
+		data := b.Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		found = true
+
+		return json.Unmarshal(data, &value)
+	})
+	if err != nil {
+		return value, false, fmt.Errorf("failed to get %v/%v: %w", c.bucket, key, err)
+	}
+
+	return value, found, nil
+}
+
+// All reads every value in the collection, keyed by their keys
+func (c Collection[T]) All() (map[string]T, error) {
+	if c.s == nil {
+		return nil, ErrClosed
+	}
+
+	values := make(map[string]T)
+	err := c.s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(c.bucket))
+		if b == nil {
+			return nil
+		}
+
+		return b.ForEach(func(k []byte, data []byte) error {
+			var value T
+			if err := json.Unmarshal(data, &value); err != nil {
+				return fmt.Errorf("%v: %w", string(k), err)
+			}
+			values[string(k)] = value
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %v: %w", c.bucket, err)
+	}
+
+	return values, nil
+}
+
+// Put writes the value of the key
+func (c Collection[T]) Put(key string, value T) error {
+	return c.Update(key, func(v *T) error {
+		*v = value
+		return nil
+	})
+}
+
+// Update changes the value of the key in a single transaction. The value is the zero value if the key doesn't exist.
+func (c Collection[T]) Update(key string, f func(value *T) error) error {
+	if c.s == nil {
+		return ErrClosed
+	}
+
+	err := c.s.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte(c.bucket))
+		if err != nil {
+			return err
+		}
+
+		var value T
+		if data := b.Get([]byte(key)); data != nil {
+			if err := json.Unmarshal(data, &value); err != nil {
+				return err
+			}
+		}
+
+		if err := f(&value); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(key), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %v/%v: %w", c.bucket, key, err)
+	}
+
+	return nil
+}
+
+// Delete removes the key
+func (c Collection[T]) Delete(key string) error {
+	if c.s == nil {
+		return ErrClosed
+	}
+
+	err := c.s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(c.bucket))
+		if b == nil {
+			return nil
+		}
+
+		return b.Delete([]byte(key))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %v/%v: %w", c.bucket, key, err)
+	}
+
+	return nil
+}
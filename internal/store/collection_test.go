@@ -0,0 +1,102 @@
+package store
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// testValue is a value stored in a collection
+type testValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCollection(t *testing.T) {
+	c := NewCollection[testValue](openTestStore(t), "test")
+
+	if value, found, err := c.Get("a"); err != nil || found || value != (testValue{}) {
+		t.Errorf("Get() of a missing key = %+v, %v, %v, want the zero value", value, found, err)
+	}
+	if all, err := c.All(); err != nil || len(all) != 0 {
+		t.Errorf("All() of a new collection = %v, %v, want none", all, err)
+	}
+
+	if err := c.Put("a", testValue{Name: "Ash", Count: 1}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if err := c.Put("b", testValue{Name: "Misty"}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if value, found, err := c.Get("a"); err != nil || !found || value != (testValue{Name: "Ash", Count: 1}) {
+		t.Errorf("Get() = %+v, %v, %v, want Ash", value, found, err)
+	}
+
+	err := c.Update("a", func(value *testValue) error {
+		value.Count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	// A failed update changes nothing
+	failed := errors.New("failed")
+	err = c.Update("b", func(value *testValue) error {
+		value.Count = 10
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("Update() error = %v, want %v", err, failed)
+	}
+
+	want := map[string]testValue{"a": {Name: "Ash", Count: 2}, "b": {Name: "Misty"}}
+	if all, err := c.All(); err != nil || !reflect.DeepEqual(all, want) {
+		t.Errorf("All() = %v, %v, want %v", all, err, want)
+	}
+
+	if err := c.Delete("a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := c.Delete("missing"); err != nil {
+		t.Errorf("Delete() of a missing key error = %v", err)
+	}
+	if _, found, _ := c.Get("a"); found {
+		t.Error("Get() found a deleted key")
+	}
+	if all, err := c.All(); err != nil || !reflect.DeepEqual(all, map[string]testValue{"b": {Name: "Misty"}}) {
+		t.Errorf("All() after Delete() = %v, %v", all, err)
+	}
+}
+
+func TestCollectionInvalidValue(t *testing.T) {
+	s := openTestStore(t)
+	if err := NewCollection[string](s, "test").Put("a", "not a number"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewCollection[int](s, "test")
+	if _, _, err := c.Get("a"); err == nil {
+		t.Error("Get() of an invalid value error = nil")
+	}
+	if _, err := c.All(); err == nil {
+		t.Error("All() with an invalid value error = nil")
+	}
+}
+
+func TestCollectionClosed(t *testing.T) {
+	var c Collection[string]
+
+	if _, _, err := c.Get("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Get() error = %v, want ErrClosed", err)
+	}
+	if _, err := c.All(); !errors.Is(err, ErrClosed) {
+		t.Errorf("All() error = %v, want ErrClosed", err)
+	}
+	if err := c.Put("a", "b"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Put() error = %v, want ErrClosed", err)
+	}
+	if err := c.Delete("a"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Delete() error = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,89 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// migration upgrades the database by one schema version. dir is where older versions kept their JSON files.
+type migration struct {
+	description string
+	migrate     func(tx *bolt.Tx, dir string) error
+}
+
+// migrations are run in order. The schema version is the number of migrations that ran, so migrations must never
+// be removed or reordered, only appended.
+var migrations = []migration{
+	{"create buckets", createBuckets},
+	{"import JSON files", importJSONFiles},
+	{"create accounts bucket", createAccountsBucket},
+	{"create reverts bucket", createRevertsBucket},
+}
+
+// createBuckets creates the buckets of the bot's data
+func createBuckets(tx *bolt.Tx, _ string) error {
+	for _, name := range []string{Guilds, Permissions, Dashboards} {
+		if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importJSONFiles copies the JSON files that older versions kept in the data directory into their buckets. The files
+// are left in place.
+func importJSONFiles(tx *bolt.Tx, dir string) error {
+	files := map[string]string{
+		"guilds.json":      Guilds,
+		"permissions.json": Permissions,
+		"dashboards.json":  Dashboards,
+	}
+
+	for file, bucket := range files {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("invalid %v: %w", file, err)
+		}
+
+		b, err := tx.CreateBucketIfNotExists([]byte(bucket))
+		if err != nil {
+			return err
+		}
+		for key, value := range values {
+			if err := b.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+
+		log.Printf("Imported %v entries from %v", len(values), file)
+	}
+
+	return nil
+}
+
+// createAccountsBucket creates the bucket of the Minecraft accounts linked to Discord users
+func createAccountsBucket(tx *bolt.Tx, _ string) error {
+	_, err := tx.CreateBucketIfNotExists([]byte(Accounts))
+	return err
+}
+
+// createRevertsBucket creates the bucket of the instance types resized servers are reverted to
+func createRevertsBucket(tx *bolt.Tx, _ string) error {
+	_, err := tx.CreateBucketIfNotExists([]byte(Reverts))
+	return err
+}
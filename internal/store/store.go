@@ -0,0 +1,243 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	defaultDataDir = "data"
+	defaultName    = "seigetsu.db"
+
+	metaBucket = "meta"
+	versionKey = "version"
+)
+
+// Buckets of the bot's data. Each one maps string keys, usually guild IDs, to JSON values.
+const (
+	Guilds      = "guilds"
+	Permissions = "permissions"
+	Dashboards  = "dashboards"
+
+	// Accounts are keyed by Discord user ID
+	Accounts = "accounts"
+
+	// Reverts are the instance types to revert resized servers to, keyed by server key
+	Reverts = "reverts"
+)
+
+var (
+	ErrClosed     = errors.New("store is not open")
+	ErrNewVersion = errors.New("data is from a newer version of the bot")
+)
+
+// Store is the bot's embedded database
+type Store struct {
+	db *bolt.DB
+
+	// dir is where older versions of the bot kept their JSON files
+	dir string
+}
+
+// DefaultPath returns the path of the database from STORE_PATH, falling back to seigetsu.db in DATA_DIR
+func DefaultPath() string {
+	if path, ok := os.LookupEnv("STORE_PATH"); ok {
+		return path
+	}
+
+	dir, ok := os.LookupEnv("DATA_DIR")
+	if !ok {
+		dir = defaultDataDir
+	}
+
+	return filepath.Join(dir, defaultName)
+}
+
+// Open opens the database at path, creating it if needed, and migrates it to the current version.
+// JSON files of older versions are imported from the directory of the database.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %v: %w", path, err)
+	}
+
+	s := &Store{db: db, dir: filepath.Dir(path)}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Version returns the schema version of the database
+func (s *Store) Version() (int, error) {
+	var version int
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		version, err = getVersion(tx)
+		return err
+	})
+
+	return version, err
+}
+
+// migrate runs every migration the database hasn't had yet, each in its own transaction
+func (s *Store) migrate() error {
+	for {
+		done := false
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			version, err := getVersion(tx)
+			if err != nil {
+				return err
+			}
+			if version > len(migrations) {
+				return fmt.Errorf("%w: schema version %v, supported up to %v", ErrNewVersion, version, len(migrations))
+			}
+			if version == len(migrations) {
+				done = true
+				return nil
+			}
+
+			m := migrations[version]
+			log.Printf("Migrating store to version %v: %v", version+1, m.description)
+			if err := m.migrate(tx, s.dir); err != nil {
+				return fmt.Errorf("failed to migrate store to version %v: %w", version+1, err)
+			}
+
+			return setVersion(tx, version+1)
+		})
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// getVersion reads the schema version, which is 0 for a new database
+func getVersion(tx *bolt.Tx) (int, error) {
+	meta := tx.Bucket([]byte(metaBucket))
+	if meta == nil {
+		return 0, nil
+	}
+
+	value := meta.Get([]byte(versionKey))
+	if value == nil {
+		return 0, nil
+	}
+
+	return strconv.Atoi(string(value))
+}
+
+// setVersion writes the schema version
+func setVersion(tx *bolt.Tx, version int) error {
+	meta, err := tx.CreateBucketIfNotExists([]byte(metaBucket))
+	if err != nil {
+		return err
+	}
+
+	return meta.Put([]byte(versionKey), []byte(strconv.Itoa(version)))
+}
+
+// dump is the format of exported data
+type dump struct {
+	Version int                                   `json:"version"`
+	Buckets map[string]map[string]json.RawMessage `json:"buckets"`
+}
+
+// Export writes every bucket and the schema version as JSON
+func (s *Store) Export(w io.Writer) error {
+	d := dump{Buckets: make(map[string]map[string]json.RawMessage)}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		if d.Version, err = getVersion(tx); err != nil {
+			return err
+		}
+
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if string(name) == metaBucket {
+				return nil
+			}
+
+			values := make(map[string]json.RawMessage)
+			err := b.ForEach(func(k []byte, v []byte) error {
+				values[string(k)] = append(json.RawMessage(nil), v...)
+				return nil
+			})
+			d.Buckets[string(name)] = values
+
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(d)
+}
+
+// Import replaces all data with data written by Export and migrates it if it is from an older version
+func (s *Store) Import(r io.Reader) error {
+	var d dump
+	if err := json.NewDecoder(r).Decode(&d); err != nil {
+		return fmt.Errorf("invalid export: %w", err)
+	}
+	if d.Version > len(migrations) {
+		return fmt.Errorf("%w: schema version %v, supported up to %v", ErrNewVersion, d.Version, len(migrations))
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		// Drop every bucket so nothing from before the import is left behind
+		var names [][]byte
+		if err := tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			names = append(names, append([]byte(nil), name...))
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, name := range names {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+
+		for name, values := range d.Buckets {
+			b, err := tx.CreateBucket([]byte(name))
+			if err != nil {
+				return err
+			}
+			for key, value := range values {
+				if err := b.Put([]byte(key), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		return setVersion(tx, d.Version)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.migrate()
+}
@@ -0,0 +1,241 @@
+package store
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// openAt creates a database at path that had the first version migrations, like one written by an older version of
+// the bot
+func openAt(t *testing.T, path string, version int) {
+	t.Helper()
+
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, m := range migrations[:min(version, len(migrations))] {
+			if err := m.migrate(tx, filepath.Dir(path)); err != nil {
+				return err
+			}
+		}
+		if version == 0 {
+			return nil
+		}
+
+		return setVersion(tx, version)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// openTestStore opens a new store in a temporary directory
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+
+	s, err := Open(filepath.Join(t.TempDir(), defaultName))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+// buckets returns the names of the buckets of the store, other than the meta bucket
+func buckets(t *testing.T, s *Store) []string {
+	t.Helper()
+
+	var names []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			if string(name) != metaBucket {
+				names = append(names, string(name))
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return names
+}
+
+func TestOpenMigrates(t *testing.T) {
+	allBuckets := []string{Accounts, Dashboards, Guilds, Permissions, Reverts}
+
+	tests := []struct {
+		name    string
+		version int
+
+		// imported is whether the JSON files of older versions are imported
+		imported bool
+	}{
+		{name: "new", version: 0, imported: true},
+		{name: "version 1", version: 1, imported: true},
+		{name: "version 2", version: 2},
+		{name: "version 3", version: 3},
+		{name: "current", version: len(migrations)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, defaultName)
+			openAt(t, path, tt.version)
+			if err := os.WriteFile(filepath.Join(dir, "guilds.json"), []byte(`{"1": {"language": "ja"}}`), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := Open(path)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer s.Close()
+
+			if version, err := s.Version(); err != nil || version != len(migrations) {
+				t.Errorf("Version() = %v, %v, want %v", version, err, len(migrations))
+			}
+			if got := buckets(t, s); !reflect.DeepEqual(got, allBuckets) {
+				t.Errorf("buckets are %v, want %v", got, allBuckets)
+			}
+
+			_, imported, err := NewCollection[map[string]string](s, Guilds).Get("1")
+			if err != nil || imported != tt.imported {
+				t.Errorf("guilds.json imported = %v, %v, want %v", imported, err, tt.imported)
+			}
+		})
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	t.Run("newer version", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), defaultName)
+		openAt(t, path, len(migrations)+1)
+
+		if _, err := Open(path); !errors.Is(err, ErrNewVersion) {
+			t.Errorf("Open() error = %v, want ErrNewVersion", err)
+		}
+	})
+
+	t.Run("invalid JSON file", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "permissions.json"), []byte("{"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Open(filepath.Join(dir, defaultName)); err == nil || !strings.Contains(err.Error(), "permissions.json") {
+			t.Errorf("Open() error = %v, want an invalid permissions.json", err)
+		}
+	})
+}
+
+func TestExportImport(t *testing.T) {
+	src := openTestStore(t)
+	if err := NewCollection[string](src, Reverts).Put("us-west-2/i-1", "t3.large"); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewCollection[[]string](src, Accounts).Put("40", []string{"Ash"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var export bytes.Buffer
+	if err := src.Export(&export); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	// Importing replaces the data, so the destination's own data is gone
+	dst := openTestStore(t)
+	if err := NewCollection[string](dst, Reverts).Put("us-east-1/i-2", "r5.xlarge"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Import(bytes.NewReader(export.Bytes())); err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	reverts, err := NewCollection[string](dst, Reverts).All()
+	if err != nil || !reflect.DeepEqual(reverts, map[string]string{"us-west-2/i-1": "t3.large"}) {
+		t.Errorf("reverts after Import() = %v, %v", reverts, err)
+	}
+	accounts, err := NewCollection[[]string](dst, Accounts).All()
+	if err != nil || !reflect.DeepEqual(accounts, map[string][]string{"40": {"Ash"}}) {
+		t.Errorf("accounts after Import() = %v, %v", accounts, err)
+	}
+
+	var again bytes.Buffer
+	if err := dst.Export(&again); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if again.String() != export.String() {
+		t.Errorf("export after Import() is\n%v\nwant\n%v", again.String(), export.String())
+	}
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		errIs   error
+
+		// want are the reverts after the import, which are unchanged if it fails
+		want map[string]string
+	}{
+		{
+			name:  "older version",
+			input: `{"version": 2, "buckets": {"guilds": {"1": {}}, "permissions": {}, "dashboards": {}}}`,
+			want:  map[string]string{},
+		},
+		{
+			name:    "newer version",
+			input:   `{"version": 99, "buckets": {}}`,
+			wantErr: true,
+			errIs:   ErrNewVersion,
+			want:    map[string]string{"us-west-2/i-1": "t3.large"},
+		},
+		{
+			name:    "invalid",
+			input:   `{"version":`,
+			wantErr: true,
+			want:    map[string]string{"us-west-2/i-1": "t3.large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := openTestStore(t)
+			reverts := NewCollection[string](s, Reverts)
+			if err := reverts.Put("us-west-2/i-1", "t3.large"); err != nil {
+				t.Fatal(err)
+			}
+
+			err := s.Import(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr || tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Errorf("Import() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := buckets(t, s); !slices.Contains(got, Accounts) || !slices.Contains(got, Reverts) {
+				t.Errorf("buckets after Import() are %v, want them migrated", got)
+			}
+
+			if version, err := s.Version(); err != nil || version != len(migrations) {
+				t.Errorf("Version() = %v, %v, want %v", version, err, len(migrations))
+			}
+			if got, err := reverts.All(); err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reverts are %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -5,21 +5,36 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/kn-lim/seigetsu-bot/internal/discord"
+	"github.com/kn-lim/seigetsu-bot/internal/store"
 )
 
 // Bot parameters
 var (
-	GuildID        = flag.String("guild", "", "Test guild ID. If not passed - bot registers commands globally")
-	RemoveCommands = flag.Bool("rmcmd", true, "Remove all commands after shutting down or not")
+	GuildIDs = flag.String("guild", "", "Comma-separated guild IDs. If not passed - bot registers commands globally")
+	Export   = flag.String("export", "", "Export the stored data to the file and exit")
+	Import   = flag.String("import", "", "Replace the stored data with the file from -export and exit")
+
+	// RemoveCommands is deprecated and does nothing since commands are only overwritten when they change
+	RemoveCommands = flag.Bool("rmcmd", true, "Deprecated: does nothing, commands are kept registered after shutting down")
 )
 
 var s *discordgo.Session
 
-func init() { flag.Parse() }
+func init() {
+	flag.Parse()
+
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "rmcmd" {
+			log.Println("Warning: -rmcmd is deprecated and does nothing, commands are kept registered after shutting down")
+		}
+	})
+}
 
 func init() {
 	var err error
@@ -31,49 +46,100 @@ func init() {
 
 func init() {
 	s.AddHandler(func(s *discordgo.Session, i *discordgo.InteractionCreate) {
-		if h, ok := discord.CommandHandlers[i.ApplicationCommandData().Name]; ok {
-			h(s, i)
+		switch i.Type {
+		case discordgo.InteractionApplicationCommand:
+			if h, ok := discord.CommandHandlers[i.ApplicationCommandData().Name]; ok {
+				h(s, i)
+			}
+		case discordgo.InteractionApplicationCommandAutocomplete:
+			if h, ok := discord.AutocompleteHandlers[i.ApplicationCommandData().Name]; ok {
+				h(s, i)
+			}
+		case discordgo.InteractionMessageComponent:
+			id, _, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+			if h, ok := discord.ComponentHandlers[id]; ok {
+				h(s, i)
+			}
 		}
 	})
 }
 
 func main() {
+	st, err := store.Open(store.DefaultPath())
+	if err != nil {
+		log.Fatalf("Cannot open the store: %v", err)
+	}
+	defer st.Close()
+
+	if *Export != "" {
+		exportStore(st, *Export)
+		return
+	}
+	if *Import != "" {
+		importStore(st, *Import)
+		return
+	}
+
+	discord.SetStore(st)
+
 	s.AddHandler(func(s *discordgo.Session, r *discordgo.Ready) {
 		log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
 	})
-	err := s.Open()
+	err = s.Open()
 	if err != nil {
 		log.Fatalf("Cannot open the session: %v", err)
 	}
 
-	log.Println("Adding commands...")
-	registeredCommands := make([]*discordgo.ApplicationCommand, len(discord.Commands))
-	for i, v := range discord.Commands {
-		cmd, err := s.ApplicationCommandCreate(s.State.User.ID, *GuildID, v)
-		if err != nil {
-			log.Panicf("Cannot create '%v' command: %v", v.Name, err)
+	discord.StartPresence(s)
+	discord.StartNotifications(s)
+	discord.StartSpotWatch(s)
+	discord.StartSpawnAnnouncements(s)
+	discord.ResumeDashboards(s)
+
+	log.Println("Registering commands...")
+	for _, guildID := range strings.Split(*GuildIDs, ",") {
+		if err := discord.RegisterCommands(s, strings.TrimSpace(guildID)); err != nil {
+			log.Panicf("Cannot register commands: %v", err)
 		}
-		log.Printf("Added '%v' command", v.Name)
-		registeredCommands[i] = cmd
 	}
 
 	defer s.Close()
 
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	log.Println("Press Ctrl+C to exit")
 	<-stop
 
-	if *RemoveCommands {
-		log.Println("Removing commands...")
-		for _, v := range registeredCommands {
-			err := s.ApplicationCommandDelete(s.State.User.ID, *GuildID, v.ID)
-			if err != nil {
-				log.Panicf("Cannot delete '%v' command: %v", v.Name, err)
-			}
-			log.Printf("Removed '%v' command", v.Name)
-		}
+	log.Println("Gracefully shutting down.")
+
+	// Let in-flight operations tell their users they were cancelled before the session closes
+	discord.Shutdown()
+}
+
+// exportStore writes the stored data to the file
+func exportStore(st *store.Store, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Fatalf("Cannot create %v: %v", path, err)
 	}
+	defer f.Close()
 
-	log.Println("Gracefully shutting down.")
+	if err := st.Export(f); err != nil {
+		log.Fatalf("Cannot export the store: %v", err)
+	}
+	log.Printf("Exported the store to %v", path)
+}
+
+// importStore replaces the stored data with the file
+func importStore(st *store.Store, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Cannot open %v: %v", path, err)
+	}
+	defer f.Close()
+
+	if err := st.Import(f); err != nil {
+		log.Fatalf("Cannot import the store: %v", err)
+	}
+	log.Printf("Imported the store from %v", path)
 }